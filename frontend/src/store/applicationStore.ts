@@ -1,25 +1,28 @@
 import { reactive } from 'vue'
 
 export interface ApplicationStore {
-  // 应用配置
-  botToken: string
+  // 应用配置，Bot Token 只保存在后端，前端只记录保存状态
+  botTokenSaved: boolean
+  botTokenMasked: string
   proxyEnabled: boolean
   proxyURL: string
 
   // 配置设置方法
-  setBotToken: (token: string) => void
+  setBotTokenStatus: (saved: boolean, masked: string) => void
   setProxySettings: (enabled: boolean, url: string) => void
 }
 
 export const applicationStore = reactive<ApplicationStore>({
   // 应用配置
-  botToken: '',
+  botTokenSaved: false,
+  botTokenMasked: '',
   proxyEnabled: false,
   proxyURL: 'http://127.0.0.1:7890',
 
   // 配置设置方法
-  setBotToken(token: string) {
-    this.botToken = token
+  setBotTokenStatus(saved: boolean, masked: string) {
+    this.botTokenSaved = saved
+    this.botTokenMasked = masked
   },
 
   setProxySettings(enabled: boolean, url: string) {
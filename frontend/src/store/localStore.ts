@@ -3,6 +3,7 @@ import { memeStore } from './memeStore'
 import { themeStore } from './themeStore'
 import { ALL_MEMES_PATH_KEY, ROOT_PATH_KEY, STAR_MEMES_KEY, BOT_TOKEN_KEY, PROXY_ENABLED_KEY, PROXY_URL_KEY } from '@/utils/common'
 import { applicationStore } from './applicationStore'
+import { GetBotTokenStatus, SetBotToken } from '@wailsjs/go/memeFile/MemeFile'
 
 export interface LocalStore {}
 
@@ -33,13 +34,7 @@ watch(() => memeStore.starMemes, (newValue) => {
   }
 }, { deep: true })
 
-// 应用配置持久化（迁回 localStore）
-watch(() => applicationStore.botToken, (newValue) => {
-  if (window) {
-    window.localStorage.setItem(BOT_TOKEN_KEY, newValue)
-  }
-})
-
+// 应用配置持久化（迁回 localStore），Bot Token 由后端保存到系统凭据存储
 watch(() => applicationStore.proxyEnabled, (newValue) => {
   if (window) {
     window.localStorage.setItem(PROXY_ENABLED_KEY, newValue.toString())
@@ -79,10 +74,7 @@ export function initializeStoreFromCache() {
     }
     
     // 恢复应用网络配置
-    const cachedBotToken = window.localStorage.getItem(BOT_TOKEN_KEY)
-    if (cachedBotToken) {
-      applicationStore.botToken = cachedBotToken
-    }
+    restoreBotToken()
 
     const cachedProxyEnabled = window.localStorage.getItem(PROXY_ENABLED_KEY)
     if (cachedProxyEnabled) {
@@ -95,3 +87,23 @@ export function initializeStoreFromCache() {
     }
   }
 }
+
+// 从后端读取 Bot Token 的保存状态，旧版本保存在 localStorage 中的 Token 迁移到后端后删除
+async function restoreBotToken() {
+  const cachedBotToken = window.localStorage.getItem(BOT_TOKEN_KEY)
+  if (cachedBotToken) {
+    try {
+      await SetBotToken(cachedBotToken)
+      window.localStorage.removeItem(BOT_TOKEN_KEY)
+    } catch (error) {
+      console.error('迁移 Bot Token 失败:', error)
+    }
+  }
+
+  try {
+    const status = await GetBotTokenStatus()
+    applicationStore.setBotTokenStatus(status.saved, status.masked)
+  } catch (error) {
+    console.error('读取 Bot Token 状态失败:', error)
+  }
+}
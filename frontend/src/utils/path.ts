@@ -8,10 +8,18 @@ export const joinPath = (path: string, dir: string) => {
   return path + '/' + dir
 }
 
+// 对路径中的每一段做 URL 编码，保留分隔符，避免 % # ? & 等字符被解析为地址的一部分
+const encodePathSegments = (path: string) => {
+  return path
+    .split(/([\\/])/)
+    .map((segment, index) => (index % 2 === 1 ? segment : encodeURIComponent(segment)))
+    .join('')
+}
+
 export const joinShowImgPath = (path: string, dir: string) => {
   // 检查参数是否有效
   if (!path || path === '') {
-    return dir
+    return encodePathSegments(dir)
   }
   
   if (path.match(/^[a-zA-Z]:\\/)) {
@@ -19,9 +27,9 @@ export const joinShowImgPath = (path: string, dir: string) => {
     const drive = path.charAt(0)
     const restPath = path.substring(2) // 跳过盘符和冒号
     
-    return '\\' + drive + restPath + '\\' + dir
+    return encodePathSegments('\\' + drive + restPath + '\\' + dir)
   }
   
   // Unix/Mac格式: 直接使用正斜杠拼接
-  return path + '/' + dir
+  return encodePathSegments(path + '/' + dir)
 }
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+
+	"mymeme/memeFile/i18n"
 )
 
 type FileLoader struct {
@@ -19,12 +21,14 @@ func NewFileLoader() *FileLoader {
 func (h *FileLoader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	log.Println("ServeHTTP:", r.URL)
 
+	// 前端通过 encodePathSegments（frontend/src/utils/path.ts）对每一段路径做 URL 编码，
+	// r.URL.Path 已是解码后的路径，文件名中的 % # ? & { } 等字符可以原样还原
 	filePath := r.URL.Path
 
 	// 处理不同操作系统的路径格式
 	var fileDir string
 
-	if runtime.GOOS == "windows" {
+	if runtime.GOOS == "windows" && len(filePath) >= 3 {
 		// Windows格式: /\c\path\to\file -> c:\path\to\file
 		rootPath := filePath[0:3]
 		fileDir = rootPath[1:2] + ":" + filePath[3:]
@@ -37,7 +41,11 @@ func (h *FileLoader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	f, err := os.OpenFile(fileDir, os.O_RDONLY, 0)
 	if err != nil {
 		log.Printf("打开文件失败: %s, 错误: %v", fileDir, err)
-		w.WriteHeader(500)
+		status := http.StatusInternalServerError
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, i18n.T(i18n.ErrOpenFile, fileDir), status)
 		return
 	}
 	// 确保文件被关闭
@@ -47,7 +55,7 @@ func (h *FileLoader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	bs, err := io.ReadAll(f)                         // Copy\ReadAll
 	if err != nil {
 		log.Printf("读取文件失败: %s, 错误: %v", fileDir, err)
-		w.WriteHeader(500)
+		http.Error(w, i18n.T(i18n.ErrReadFile, fileDir), http.StatusInternalServerError)
 		return
 	}
 	w.Write(bs)
@@ -0,0 +1,149 @@
+package memeFile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// orderPrefix 获取文件夹名的序号前缀（如 03_name 中的 03），没有前缀时返回空字符串
+func (m *MemeFile) orderPrefix(folderName string) string {
+	clean := m.cleanFolderName(folderName)
+	if clean == folderName {
+		return ""
+	}
+	return strings.TrimSuffix(folderName, "_"+clean)
+}
+
+// CreateCategory 在根目录下创建分类文件夹，返回实际创建的文件夹名
+// 按重命名方式排序且已有文件夹带序号前缀时，新文件夹使用下一个序号排在最后
+func (m *MemeFile) CreateCategory(rootPath string, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	if !m.fileUtils.IsDir(rootPath) {
+		return "", i18n.Errorf(i18n.ErrRootNotFound, rootPath)
+	}
+
+	if m.orderMode() == OrderModeRename {
+		last, prefixed := 0, false
+		for _, dir := range m.GetDirs(rootPath) {
+			if n, err := strconv.Atoi(m.orderPrefix(dir)); err == nil {
+				prefixed = true
+				if n > last {
+					last = n
+				}
+			}
+		}
+		if prefixed {
+			name = fmt.Sprintf("%02d_%s", last+1, m.cleanFolderName(name))
+		}
+	}
+
+	path, err := resolvePath(rootPath, name)
+	if err != nil {
+		return "", err
+	}
+	if m.fileUtils.PathExists(path) {
+		return "", i18n.Errorf(i18n.ErrFileExists, name)
+	}
+	if err := os.Mkdir(path, 0755); err != nil {
+		return "", i18n.Wrap(err, i18n.ErrCreateDir)
+	}
+	return name, nil
+}
+
+// RenameCategory 重命名分类文件夹，保留原有的序号前缀，返回新的文件夹名
+// 新名称中自带的序号前缀会被忽略，避免打乱已保存的顺序
+func (m *MemeFile) RenameCategory(rootPath string, folderCode string, newName string) (string, error) {
+	newName = strings.TrimSpace(newName)
+	if err := validateName(newName); err != nil {
+		return "", err
+	}
+	if _, err := resolvePath(rootPath, folderCode); err != nil {
+		return "", err
+	}
+
+	finalName := newName
+	if prefix := m.orderPrefix(folderCode); prefix != "" {
+		finalName = prefix + "_" + m.cleanFolderName(newName)
+	}
+	if finalName == folderCode {
+		return finalName, nil
+	}
+	if _, err := resolvePath(rootPath, finalName); err != nil {
+		return "", err
+	}
+
+	if err := m.renameFile(rootPath, folderCode, finalName); err != nil {
+		return "", err
+	}
+
+	entry := newHistoryEntry(HistoryRename, rootPath, folderCode)
+	entry.Steps = []renameStep{{Old: folderCode, Final: finalName}}
+	m.recordHistory(entry)
+	return finalName, nil
+}
+
+// MergeCategories 将 fromCode 分类中的所有表情移动到 toCode 分类
+// 全部移动成功后源文件夹（包括贴纸清单等剩余文件）移动到回收站，否则保留源文件夹
+func (m *MemeFile) MergeCategories(rootPath string, fromCode string, toCode string, conflict string) (*TransferResult, error) {
+	fromDir, err := resolvePath(rootPath, fromCode)
+	if err != nil {
+		return nil, err
+	}
+	toDir, err := resolvePath(rootPath, toCode)
+	if err != nil {
+		return nil, err
+	}
+	if fromDir == toDir {
+		return nil, i18n.Errorf(i18n.ErrSameFolder, fromCode)
+	}
+	if !m.fileUtils.IsDir(fromDir) {
+		return nil, i18n.Errorf(i18n.ErrFolderNotFound, fromCode)
+	}
+	if !m.fileUtils.IsDir(toDir) {
+		return nil, i18n.Errorf(i18n.ErrFolderNotFound, toCode)
+	}
+
+	result := &TransferResult{From: fromCode, To: toCode, Items: []TransferItem{}}
+	if images := m.GetImages(fromDir); len(images) > 0 {
+		result, err = m.transferMemes(rootPath, fromCode, toCode, images, conflict, true)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if result.Skipped == 0 && result.Failed == 0 {
+		if err := m.DeleteCategory(rootPath, fromCode); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// DeleteCategory 删除分类文件夹（移动到回收站）
+func (m *MemeFile) DeleteCategory(rootPath string, folderCode string) error {
+	folderPath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return err
+	}
+	if !m.fileUtils.IsDir(folderPath) {
+		return i18n.Errorf(i18n.ErrFolderNotFound, folderCode)
+	}
+
+	entry := newHistoryEntry(HistoryDelete, rootPath, folderCode)
+	item, err := m.moveToTrash(folderPath, entry.ID)
+	if err != nil {
+		return i18n.Wrap(err, i18n.ErrDeleteFolder)
+	}
+
+	entry.Name = item.Name
+	entry.TrashID = item.ID
+	m.recordHistory(entry)
+	return nil
+}
@@ -0,0 +1,135 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// categoryMetaFileName 分类信息文件名，保存在分类文件夹下
+const categoryMetaFileName = ".category.json"
+
+// categoryColorFormat 颜色格式：#RGB、#RRGGBB 或 #RRGGBBAA
+var categoryColorFormat = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// CategoryMeta 结构体 - 分类的显示信息，不修改文件夹名即可调整标签页的显示
+type CategoryMeta struct {
+	DisplayName string `json:"displayName"` // 显示名称，为空时使用去掉序号前缀的文件夹名
+	Icon        string `json:"icon"`        // 图标文件名，为空或文件不存在时使用第一张图片
+	Description string `json:"description"` // 描述
+	Color       string `json:"color"`       // 标签颜色，如 #ff8800
+}
+
+// isEmpty 判断是否没有任何自定义信息
+func (c CategoryMeta) isEmpty() bool {
+	return c == CategoryMeta{}
+}
+
+// readCategoryMeta 读取分类信息，文件不存在或损坏时返回空信息
+func (m *MemeFile) readCategoryMeta(dirPath string) CategoryMeta {
+	var meta CategoryMeta
+	if err := m.fileUtils.ReadJSON(filepath.Join(dirPath, categoryMetaFileName), &meta); err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取分类信息失败 %s: %v", dirPath, err)
+		}
+		return CategoryMeta{}
+	}
+	return meta
+}
+
+// writeCategoryMeta 写入分类信息，没有任何自定义信息时删除文件
+func (m *MemeFile) writeCategoryMeta(dirPath string, meta CategoryMeta) error {
+	path := filepath.Join(dirPath, categoryMetaFileName)
+	if meta.isEmpty() {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return i18n.Wrap(err, i18n.ErrSaveCategoryMeta)
+		}
+		return nil
+	}
+	if err := m.fileUtils.WriteJSON(path, meta); err != nil {
+		return i18n.Wrap(err, i18n.ErrSaveCategoryMeta)
+	}
+	return nil
+}
+
+// renameMetaIcon 图标文件被重命名后同步更新分类信息，renames 为 原名称 -> 新名称
+func (m *MemeFile) renameMetaIcon(dirPath string, renames map[string]string) {
+	meta := m.readCategoryMeta(dirPath)
+	newName, ok := renames[meta.Icon]
+	if meta.Icon == "" || !ok {
+		return
+	}
+
+	meta.Icon = newName
+	if err := m.writeCategoryMeta(dirPath, meta); err != nil {
+		log.Printf("更新分类图标失败 %s: %v", dirPath, err)
+	}
+}
+
+// categoryIcon 获取分类图标，自定义图标不存在时使用第一张图片，分类中没有图片时返回空字符串
+func categoryIcon(meta CategoryMeta, imageNames []string) string {
+	if len(imageNames) == 0 {
+		return ""
+	}
+	for _, name := range imageNames {
+		if name == meta.Icon {
+			return name
+		}
+	}
+	return imageNames[0]
+}
+
+// categoryDisplayName 获取分类显示名称，未设置时使用去掉序号前缀的文件夹名
+func (m *MemeFile) categoryDisplayName(meta CategoryMeta, dirName string) string {
+	if meta.DisplayName != "" {
+		return meta.DisplayName
+	}
+	return m.cleanFolderName(dirName)
+}
+
+// GetCategoryMeta 获取分类的显示信息
+func (m *MemeFile) GetCategoryMeta(rootPath string, folderCode string) (CategoryMeta, error) {
+	dirPath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return CategoryMeta{}, err
+	}
+	if !m.fileUtils.IsDir(dirPath) {
+		return CategoryMeta{}, i18n.Errorf(i18n.ErrFolderNotFound, folderCode)
+	}
+	return m.readCategoryMeta(dirPath), nil
+}
+
+// SetCategoryMeta 设置分类的显示名称、图标、描述和颜色，全部为空时恢复默认显示
+func (m *MemeFile) SetCategoryMeta(rootPath string, folderCode string, meta CategoryMeta) error {
+	dirPath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return err
+	}
+	if !m.fileUtils.IsDir(dirPath) {
+		return i18n.Errorf(i18n.ErrFolderNotFound, folderCode)
+	}
+
+	meta.DisplayName = strings.TrimSpace(meta.DisplayName)
+	meta.Icon = strings.TrimSpace(meta.Icon)
+	meta.Description = strings.TrimSpace(meta.Description)
+	meta.Color = strings.TrimSpace(meta.Color)
+
+	if meta.Icon != "" {
+		iconPath, err := resolvePath(dirPath, meta.Icon)
+		if err != nil {
+			return err
+		}
+		if !m.fileUtils.IsFile(iconPath) || !m.imageUtils.IsImageFile(meta.Icon) {
+			return i18n.Errorf(i18n.ErrInvalidIcon, meta.Icon)
+		}
+	}
+	if meta.Color != "" && !categoryColorFormat.MatchString(meta.Color) {
+		return i18n.Errorf(i18n.ErrInvalidColor, meta.Color)
+	}
+
+	return m.writeCategoryMeta(dirPath, meta)
+}
@@ -0,0 +1,494 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/sticker"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// downloadQueueFileName 下载队列文件名，保存在应用配置目录下
+const downloadQueueFileName = "download_queue.json"
+
+// defaultDownloadConcurrency 默认同时下载的贴纸集合数量
+const defaultDownloadConcurrency = 2
+
+// 下载任务状态
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// DownloadJob 结构体 - 下载队列中的一个贴纸集合
+type DownloadJob struct {
+	ID             string                   `json:"id"`
+	StickerSetName string                   `json:"stickerSetName"`
+	SavePath       string                   `json:"savePath"`
+	ProfileID      string                   `json:"profileId"` // 为空时使用当前选择的转换配置
+	Status         string                   `json:"status"`
+	Error          string                   `json:"error"`
+	ErrorCode      string                   `json:"errorCode"` // 失败时的错误码，可用于前端翻译
+	Progress       sticker.DownloadProgress `json:"progress"`
+	Result         *sticker.DownloadResult  `json:"result"` // 完成后的下载结果
+	CreatedAt      time.Time                `json:"createdAt"`
+	FinishedAt     *time.Time               `json:"finishedAt"`
+}
+
+// DownloadQueueProgress 结构体 - 下载队列整体进度
+type DownloadQueueProgress struct {
+	Total     int  `json:"total"`
+	Pending   int  `json:"pending"`
+	Running   int  `json:"running"`
+	Done      int  `json:"done"`
+	Failed    int  `json:"failed"`
+	Cancelled int  `json:"cancelled"`
+	Started   bool `json:"started"` // 队列是否正在运行
+}
+
+// downloadCredentials 运行队列所需的 Bot Token 和代理设置，只保存在内存中
+type downloadCredentials struct {
+	botToken  string
+	proxyURL  string
+	needProxy bool
+}
+
+// downloadQueue 贴纸集合下载队列
+type downloadQueue struct {
+	m           *MemeFile
+	mu          sync.Mutex
+	jobs        []*DownloadJob
+	concurrency int
+	running     int
+	started     bool
+	credentials downloadCredentials
+	lastID      int64
+}
+
+// newDownloadQueue 创建下载队列并恢复上次未完成的任务
+func newDownloadQueue(m *MemeFile, concurrency int) *downloadQueue {
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+
+	q := &downloadQueue{m: m, concurrency: concurrency}
+	q.load()
+	return q
+}
+
+// queuePath 获取下载队列文件路径
+func (q *downloadQueue) queuePath() (string, error) {
+	dir, err := q.m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, downloadQueueFileName), nil
+}
+
+// load 读取持久化的队列，上次退出时正在下载的任务重新排队
+func (q *downloadQueue) load() {
+	path, err := q.queuePath()
+	if err != nil {
+		log.Printf("读取下载队列失败: %v", err)
+		return
+	}
+
+	if err := q.m.fileUtils.ReadJSON(path, &q.jobs); err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("解析下载队列失败 %s: %v", path, err)
+		}
+		q.jobs = nil
+		return
+	}
+
+	for _, job := range q.jobs {
+		if job.Status == JobRunning {
+			job.Status = JobPending
+		}
+	}
+}
+
+// save 持久化队列，调用方需持有 mu
+func (q *downloadQueue) save() {
+	path, err := q.queuePath()
+	if err != nil {
+		log.Printf("保存下载队列失败: %v", err)
+		return
+	}
+
+	if err := q.m.fileUtils.WriteJSON(path, q.jobs); err != nil {
+		log.Printf("保存下载队列失败: %v", err)
+	}
+}
+
+// nextID 生成任务ID，调用方需持有 mu
+func (q *downloadQueue) nextID() string {
+	id := time.Now().UnixNano()
+	if id <= q.lastID {
+		id = q.lastID + 1
+	}
+	q.lastID = id
+	return strconv.FormatInt(id, 36)
+}
+
+// enqueue 添加任务，已在队列中等待或下载的同名集合会被跳过
+func (q *downloadQueue) enqueue(names []string, rootPath string, profileID string) []DownloadJob {
+	q.mu.Lock()
+
+	var added []DownloadJob
+	for _, name := range names {
+		if q.hasActiveJob(name) {
+			log.Printf("贴纸集合已在下载队列中: %s", name)
+			continue
+		}
+
+		savePath, err := resolvePath(rootPath, name)
+		if err != nil {
+			log.Printf("贴纸集合保存路径无效 %s: %v", name, err)
+			continue
+		}
+
+		job := &DownloadJob{
+			ID:             q.nextID(),
+			StickerSetName: name,
+			SavePath:       savePath,
+			ProfileID:      profileID,
+			Status:         JobPending,
+			CreatedAt:      time.Now(),
+		}
+		q.jobs = append(q.jobs, job)
+		added = append(added, *job)
+	}
+	q.save()
+	q.mu.Unlock()
+
+	q.emitOverall()
+	q.schedule()
+	return added
+}
+
+// hasActiveJob 判断集合是否已在等待或下载中，调用方需持有 mu
+func (q *downloadQueue) hasActiveJob(name string) bool {
+	for _, job := range q.jobs {
+		if job.StickerSetName == name && (job.Status == JobPending || job.Status == JobRunning) {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule 在并发数允许的范围内启动等待中的任务
+func (q *downloadQueue) schedule() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.started {
+		return
+	}
+
+	for _, job := range q.jobs {
+		if q.running >= q.concurrency {
+			break
+		}
+		if job.Status != JobPending {
+			continue
+		}
+
+		job.Status = JobRunning
+		job.Error = ""
+		q.running++
+		go q.run(job.ID, *job, q.credentials)
+	}
+	q.save()
+}
+
+// run 执行一个下载任务
+func (q *downloadQueue) run(id string, job DownloadJob, cred downloadCredentials) {
+	q.emitJob(job)
+
+	botToken, err := q.m.resolveBotToken(cred.botToken)
+	var downloader *sticker.TelegramDownloader
+	if err == nil {
+		downloader, err = q.m.newDownloader(botToken, cred.proxyURL, cred.needProxy, job.ProfileID)
+	}
+	var result *sticker.DownloadResult
+	if err == nil {
+		result, err = downloader.DownloadTgStickerSet(job.StickerSetName, job.SavePath, func(progress sticker.DownloadProgress) {
+			q.updateProgress(id, progress)
+		})
+	}
+
+	q.mu.Lock()
+	q.running--
+	finished := q.find(id)
+	if finished != nil {
+		now := time.Now()
+		finished.FinishedAt = &now
+		finished.Result = result
+		if err != nil {
+			finished.Status = JobFailed
+			finished.Error = err.Error()
+			finished.ErrorCode = string(i18n.CodeOf(err))
+		} else {
+			finished.Status = JobDone
+		}
+		job = *finished
+	}
+	q.save()
+	q.mu.Unlock()
+
+	if err != nil {
+		log.Printf("下载任务失败 %s: %v", job.StickerSetName, err)
+	}
+
+	q.emitJob(job)
+	q.emitOverall()
+	q.schedule()
+}
+
+// updateProgress 更新任务进度并通知前端
+func (q *downloadQueue) updateProgress(id string, progress sticker.DownloadProgress) {
+	q.mu.Lock()
+	job := q.find(id)
+	if job == nil {
+		q.mu.Unlock()
+		return
+	}
+	job.Progress = progress
+	snapshot := *job
+	q.mu.Unlock()
+
+	q.emitJob(snapshot)
+}
+
+// find 按ID查找任务，调用方需持有 mu
+func (q *downloadQueue) find(id string) *DownloadJob {
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// overall 统计队列整体进度，调用方需持有 mu
+func (q *downloadQueue) overall() DownloadQueueProgress {
+	progress := DownloadQueueProgress{Total: len(q.jobs), Started: q.started}
+	for _, job := range q.jobs {
+		switch job.Status {
+		case JobPending:
+			progress.Pending++
+		case JobRunning:
+			progress.Running++
+		case JobDone:
+			progress.Done++
+		case JobFailed:
+			progress.Failed++
+		case JobCancelled:
+			progress.Cancelled++
+		}
+	}
+	return progress
+}
+
+// emitJob 发送单个任务的进度事件
+func (q *downloadQueue) emitJob(job DownloadJob) {
+	if q.m.ctx != nil {
+		runtime.EventsEmit(q.m.ctx, "download-queue-job", job)
+	}
+}
+
+// emitOverall 发送队列整体进度事件
+func (q *downloadQueue) emitOverall() {
+	q.mu.Lock()
+	progress := q.overall()
+	q.mu.Unlock()
+
+	if q.m.ctx != nil {
+		runtime.EventsEmit(q.m.ctx, "download-queue-progress", progress)
+	}
+}
+
+// parseStickerSetList 从粘贴的文本中提取贴纸集合名称，支持各种形式的链接、@name 和直接输入的名称
+func parseStickerSetList(text string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ' ' || r == '\t' || r == ',' || r == '，'
+	}) {
+		ref, err := sticker.ParseStickerSetRef(field)
+		if err != nil {
+			log.Printf("跳过无法解析的贴纸引用: %v", err)
+			continue
+		}
+
+		if !seen[ref.Name] {
+			seen[ref.Name] = true
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// EnqueueTgStickerSets 将多个贴纸集合加入下载队列，每个集合保存到 rootPath 下的同名文件夹
+// refs 可以是集合名称、@name 或 t.me 链接；profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) EnqueueTgStickerSets(refs []string, rootPath string, profileID string) ([]DownloadJob, error) {
+	if rootPath == "" {
+		return nil, i18n.Errorf(i18n.ErrRootRequired)
+	}
+
+	names := parseStickerSetList(strings.Join(refs, "\n"))
+	if len(names) == 0 {
+		return nil, i18n.Errorf(i18n.ErrNoStickerSets)
+	}
+
+	return m.queue.enqueue(names, rootPath, profileID), nil
+}
+
+// EnqueueTgStickerLinks 从粘贴的文本（每行一个或以空格、逗号分隔的 t.me 链接）中解析贴纸集合并加入下载队列
+func (m *MemeFile) EnqueueTgStickerLinks(text string, rootPath string, profileID string) ([]DownloadJob, error) {
+	return m.EnqueueTgStickerSets([]string{text}, rootPath, profileID)
+}
+
+// StartDownloadQueue 使用指定的 Bot Token 和代理设置开始处理下载队列，botToken 为空时使用已保存的 Token
+func (m *MemeFile) StartDownloadQueue(botToken string, proxyURL string, needProxy bool) {
+	m.queue.mu.Lock()
+	m.queue.started = true
+	m.queue.credentials = downloadCredentials{botToken: botToken, proxyURL: proxyURL, needProxy: needProxy}
+	m.queue.mu.Unlock()
+
+	m.queue.emitOverall()
+	m.queue.schedule()
+}
+
+// PauseDownloadQueue 暂停下载队列，正在下载的任务会继续完成
+func (m *MemeFile) PauseDownloadQueue() {
+	m.queue.mu.Lock()
+	m.queue.started = false
+	m.queue.mu.Unlock()
+
+	m.queue.emitOverall()
+}
+
+// GetDownloadQueue 获取下载队列中的所有任务
+func (m *MemeFile) GetDownloadQueue() []DownloadJob {
+	m.queue.mu.Lock()
+	defer m.queue.mu.Unlock()
+
+	jobs := make([]DownloadJob, 0, len(m.queue.jobs))
+	for _, job := range m.queue.jobs {
+		jobs = append(jobs, *job)
+	}
+	return jobs
+}
+
+// GetDownloadQueueProgress 获取下载队列整体进度
+func (m *MemeFile) GetDownloadQueueProgress() DownloadQueueProgress {
+	m.queue.mu.Lock()
+	defer m.queue.mu.Unlock()
+
+	return m.queue.overall()
+}
+
+// CancelDownloadJob 取消等待中的任务，正在下载的任务无法取消
+func (m *MemeFile) CancelDownloadJob(jobID string) error {
+	m.queue.mu.Lock()
+	job := m.queue.find(jobID)
+	if job == nil {
+		m.queue.mu.Unlock()
+		return i18n.Errorf(i18n.ErrJobNotFound, jobID)
+	}
+	if job.Status != JobPending {
+		m.queue.mu.Unlock()
+		return i18n.Errorf(i18n.ErrJobNotCancellable, job.StickerSetName)
+	}
+
+	job.Status = JobCancelled
+	snapshot := *job
+	m.queue.save()
+	m.queue.mu.Unlock()
+
+	m.queue.emitJob(snapshot)
+	m.queue.emitOverall()
+	return nil
+}
+
+// RetryDownloadJob 将失败或已取消的任务重新加入队列
+func (m *MemeFile) RetryDownloadJob(jobID string) error {
+	m.queue.mu.Lock()
+	job := m.queue.find(jobID)
+	if job == nil {
+		m.queue.mu.Unlock()
+		return i18n.Errorf(i18n.ErrJobNotFound, jobID)
+	}
+	if job.Status != JobFailed && job.Status != JobCancelled {
+		m.queue.mu.Unlock()
+		return i18n.Errorf(i18n.ErrJobNotRetryable, job.StickerSetName)
+	}
+
+	job.Status = JobPending
+	job.Error = ""
+	job.ErrorCode = ""
+	job.FinishedAt = nil
+	job.Result = nil
+	m.queue.save()
+	m.queue.mu.Unlock()
+
+	m.queue.emitOverall()
+	m.queue.schedule()
+	return nil
+}
+
+// ClearFinishedDownloads 从队列中移除已完成、失败和已取消的任务
+func (m *MemeFile) ClearFinishedDownloads() {
+	m.queue.mu.Lock()
+	var remaining []*DownloadJob
+	for _, job := range m.queue.jobs {
+		if job.Status == JobPending || job.Status == JobRunning {
+			remaining = append(remaining, job)
+		}
+	}
+	m.queue.jobs = remaining
+	m.queue.save()
+	m.queue.mu.Unlock()
+
+	m.queue.emitOverall()
+}
+
+// SetDownloadConcurrency 设置同时下载的贴纸集合数量
+func (m *MemeFile) SetDownloadConcurrency(concurrency int) error {
+	if concurrency < 1 || concurrency > 8 {
+		return i18n.Errorf(i18n.ErrConcurrencyRange, concurrency)
+	}
+
+	m.settingsMu.Lock()
+	m.settings.DownloadConcurrency = concurrency
+	err := m.saveSettings()
+	m.settingsMu.Unlock()
+
+	m.queue.mu.Lock()
+	m.queue.concurrency = concurrency
+	m.queue.mu.Unlock()
+
+	m.queue.schedule()
+	return err
+}
+
+// GetDownloadConcurrency 获取同时下载的贴纸集合数量
+func (m *MemeFile) GetDownloadConcurrency() int {
+	m.queue.mu.Lock()
+	defer m.queue.mu.Unlock()
+
+	return m.queue.concurrency
+}
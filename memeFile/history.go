@@ -0,0 +1,269 @@
+package memeFile
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"mymeme/memeFile/i18n"
+)
+
+// historyFileName 历史记录文件名，保存在应用配置目录下
+const historyFileName = "history.json"
+
+// maxHistory 最多保留的可撤销操作数量
+const maxHistory = 50
+
+// 操作类型
+const (
+	HistoryRename      = "rename"       // 重命名单个表情
+	HistoryBatchRename = "batch_rename" // 按顺序重命名表情或分类文件夹
+	HistoryReorder     = "reorder"      // 保存顺序清单
+	HistoryDelete      = "delete"       // 删除表情或贴纸集合（移动到回收站）
+)
+
+// HistoryEntry 结构体 - 一次可撤销的操作，记录撤销和重做所需的全部数据
+type HistoryEntry struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`  // 操作类型
+	Time  int64  `json:"time"`  // 操作时间（Unix 毫秒）
+	Dir   string `json:"dir"`   // 操作所在目录
+	Label string `json:"label"` // 用于界面显示的名称
+
+	Steps   []renameStep `json:"steps,omitempty"`   // 重命名：原名称 -> 最终名称
+	IsDir   bool         `json:"isDir,omitempty"`   // 批量重命名：是否为分类文件夹
+	Before  []string     `json:"before,omitempty"`  // 操作前的顺序清单，为空表示没有清单
+	After   []string     `json:"after,omitempty"`   // 保存的顺序清单
+	Name    string       `json:"name,omitempty"`    // 删除：文件或文件夹名
+	TrashID string       `json:"trashId,omitempty"` // 删除：回收站条目ID
+}
+
+// HistoryStatus 结构体 - 撤销/重做状态，用于界面显示
+type HistoryStatus struct {
+	CanUndo bool          `json:"canUndo"`
+	CanRedo bool          `json:"canRedo"`
+	Undo    *HistoryEntry `json:"undo"` // 下一个可撤销的操作
+	Redo    *HistoryEntry `json:"redo"` // 下一个可重做的操作
+}
+
+// historyState 持久化的撤销和重做栈，最后一个元素为最近的操作
+type historyState struct {
+	Undo []HistoryEntry `json:"undo"`
+	Redo []HistoryEntry `json:"redo"`
+}
+
+// historyPath 获取历史记录文件路径
+func (m *MemeFile) historyPath() (string, error) {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFileName), nil
+}
+
+// loadHistory 读取历史记录，文件不存在或损坏时返回空记录
+func (m *MemeFile) loadHistory() historyState {
+	var state historyState
+	path, err := m.historyPath()
+	if err != nil {
+		return state
+	}
+	if err := m.fileUtils.ReadJSON(path, &state); err != nil && !os.IsNotExist(err) {
+		log.Printf("读取历史记录失败: %v", err)
+	}
+	return state
+}
+
+// saveHistory 保存历史记录
+func (m *MemeFile) saveHistory(state historyState) {
+	path, err := m.historyPath()
+	if err == nil {
+		err = m.fileUtils.WriteJSON(path, state)
+	}
+	if err != nil {
+		log.Printf("保存历史记录失败: %v", err)
+	}
+}
+
+// newHistoryEntry 创建操作记录
+func newHistoryEntry(kind string, dir string, label string) HistoryEntry {
+	now := time.Now()
+	return HistoryEntry{
+		ID:    strconv.FormatInt(now.UnixNano(), 36),
+		Kind:  kind,
+		Time:  now.UnixMilli(),
+		Dir:   dir,
+		Label: label,
+	}
+}
+
+// recordHistory 记录一次新操作，清空重做栈，超出数量上限时丢弃最早的操作
+// 丢弃删除操作的记录不会影响回收站中的文件
+func (m *MemeFile) recordHistory(entry HistoryEntry) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	state := m.loadHistory()
+	state.Redo = nil
+	state.Undo = append(state.Undo, entry)
+	if over := len(state.Undo) - maxHistory; over > 0 {
+		state.Undo = append([]HistoryEntry(nil), state.Undo[over:]...)
+	}
+	m.saveHistory(state)
+}
+
+// invertSteps 生成撤销批量重命名的步骤
+func invertSteps(steps []renameStep) []renameStep {
+	inverted := make([]renameStep, len(steps))
+	for i, step := range steps {
+		inverted[i] = renameStep{Old: step.Final, Temp: step.Temp, Final: step.Old}
+	}
+	return inverted
+}
+
+// restoreOrder 恢复操作前的顺序清单
+func (m *MemeFile) restoreOrder(dir string, order []string) error {
+	if len(order) == 0 {
+		m.removeOrder(dir)
+		return nil
+	}
+	return m.writeOrder(dir, order)
+}
+
+// applyHistory 撤销或重做一次操作
+func (m *MemeFile) applyHistory(entry HistoryEntry, undo bool) error {
+	switch entry.Kind {
+	case HistoryRename:
+		if len(entry.Steps) == 0 {
+			return i18n.Errorf(i18n.ErrIncompleteHistory, entry.Kind)
+		}
+		step := entry.Steps[0]
+		if undo {
+			return m.renameFile(entry.Dir, step.Final, step.Old)
+		}
+		return m.renameFile(entry.Dir, step.Old, step.Final)
+
+	case HistoryBatchRename:
+		code := i18n.ErrRenameFile
+		if entry.IsDir {
+			code = i18n.ErrRenameFolder
+		}
+		if undo {
+			if err := m.renameBatch(entry.Dir, invertSteps(entry.Steps), code); err != nil {
+				return err
+			}
+			return m.restoreOrder(entry.Dir, entry.Before)
+		}
+		if err := m.renameBatch(entry.Dir, entry.Steps, code); err != nil {
+			return err
+		}
+		m.removeOrder(entry.Dir)
+		return nil
+
+	case HistoryReorder:
+		if undo {
+			return m.restoreOrder(entry.Dir, entry.Before)
+		}
+		return m.writeOrder(entry.Dir, entry.After)
+
+	case HistoryDelete:
+		if undo {
+			_, err := m.restoreTrashItem(entry.TrashID)
+			return err
+		}
+		path := filepath.Join(entry.Dir, entry.Name)
+		if !m.fileUtils.PathExists(path) {
+			return i18n.Errorf(i18n.ErrFileNotFound, entry.Name)
+		}
+		if _, err := m.moveToTrash(path, entry.TrashID); err != nil {
+			return i18n.Wrap(err, i18n.ErrDeleteFile)
+		}
+		return nil
+	}
+
+	return i18n.Errorf(i18n.ErrUnknownHistory, entry.Kind)
+}
+
+// Undo 撤销最近一次操作，返回被撤销的操作
+func (m *MemeFile) Undo() (*HistoryEntry, error) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	state := m.loadHistory()
+	if len(state.Undo) == 0 {
+		return nil, i18n.Errorf(i18n.ErrNothingToUndo)
+	}
+
+	entry := state.Undo[len(state.Undo)-1]
+	if err := m.applyHistory(entry, true); err != nil {
+		// 回收站中的条目已被恢复或清理，或记录本身不完整，这条记录无法再撤销，直接丢弃
+		if code := i18n.CodeOf(err); code == i18n.ErrTrashItemNotFound || code == i18n.ErrIncompleteHistory {
+			state.Undo = state.Undo[:len(state.Undo)-1]
+			m.saveHistory(state)
+		}
+		return nil, err
+	}
+
+	state.Undo = state.Undo[:len(state.Undo)-1]
+	state.Redo = append(state.Redo, entry)
+	m.saveHistory(state)
+	return &entry, nil
+}
+
+// Redo 重做最近一次撤销的操作，返回被重做的操作
+func (m *MemeFile) Redo() (*HistoryEntry, error) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	state := m.loadHistory()
+	if len(state.Redo) == 0 {
+		return nil, i18n.Errorf(i18n.ErrNothingToRedo)
+	}
+
+	entry := state.Redo[len(state.Redo)-1]
+	if err := m.applyHistory(entry, false); err != nil {
+		// 记录不完整时无法重做，直接丢弃
+		if i18n.CodeOf(err) == i18n.ErrIncompleteHistory {
+			state.Redo = state.Redo[:len(state.Redo)-1]
+			m.saveHistory(state)
+		}
+		return nil, err
+	}
+
+	state.Redo = state.Redo[:len(state.Redo)-1]
+	state.Undo = append(state.Undo, entry)
+	m.saveHistory(state)
+	return &entry, nil
+}
+
+// GetHistoryStatus 获取撤销/重做状态
+func (m *MemeFile) GetHistoryStatus() HistoryStatus {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	state := m.loadHistory()
+	status := HistoryStatus{CanUndo: len(state.Undo) > 0, CanRedo: len(state.Redo) > 0}
+	if status.CanUndo {
+		status.Undo = &state.Undo[len(state.Undo)-1]
+	}
+	if status.CanRedo {
+		status.Redo = &state.Redo[len(state.Redo)-1]
+	}
+	return status
+}
+
+// ClearHistory 清空撤销/重做记录，回收站中的文件不受影响
+func (m *MemeFile) ClearHistory() {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	m.saveHistory(historyState{})
+}
+
+// historyLabel 生成操作的显示名称
+func historyLabel(dir string, name string) string {
+	return fmt.Sprintf("%s/%s", filepath.Base(dir), name)
+}
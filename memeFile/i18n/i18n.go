@@ -0,0 +1,137 @@
+// Package i18n 提供带错误码的错误类型和多语言消息目录
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// 支持的语言
+const (
+	LangZhCN = "zh-CN"
+	LangEn   = "en"
+)
+
+// DefaultLanguage 默认语言
+const DefaultLanguage = LangZhCN
+
+// Code 错误码，同时作为消息目录的键
+type Code string
+
+// current 当前语言
+var current atomic.Value
+
+func init() {
+	current.Store(DefaultLanguage)
+}
+
+// Languages 返回所有支持的语言
+func Languages() []string {
+	return []string{LangZhCN, LangEn}
+}
+
+// NormalizeLanguage 将 zh、zh-Hans、en-US 等写法规范化为支持的语言，不支持时返回空字符串
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	switch {
+	case lang == "":
+		return ""
+	case strings.HasPrefix(lang, "zh"):
+		return LangZhCN
+	case strings.HasPrefix(lang, "en"):
+		return LangEn
+	}
+	return ""
+}
+
+// SetLanguage 设置当前语言，不支持的语言返回错误
+func SetLanguage(lang string) error {
+	normalized := NormalizeLanguage(lang)
+	if normalized == "" {
+		return Errorf(ErrUnsupportedLanguage, lang)
+	}
+	current.Store(normalized)
+	return nil
+}
+
+// Language 返回当前语言
+func Language() string {
+	return current.Load().(string)
+}
+
+// T 使用当前语言格式化消息
+func T(code Code, args ...any) string {
+	return TL(Language(), code, args...)
+}
+
+// TL 使用指定语言格式化消息，目录中缺少该消息时依次回退到默认语言和错误码本身
+func TL(lang string, code Code, args ...any) string {
+	format, ok := catalogue[lang][code]
+	if !ok {
+		format, ok = catalogue[DefaultLanguage][code]
+	}
+	if !ok {
+		format = string(code)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Catalogue 返回指定语言的完整消息目录（错误码 -> 格式字符串），供前端翻译错误码
+func Catalogue(lang string) map[string]string {
+	lang = NormalizeLanguage(lang)
+	if lang == "" {
+		lang = Language()
+	}
+
+	messages := make(map[string]string, len(catalogue[DefaultLanguage]))
+	for code, format := range catalogue[DefaultLanguage] {
+		messages[string(code)] = format
+	}
+	for code, format := range catalogue[lang] {
+		messages[string(code)] = format
+	}
+	return messages
+}
+
+// Error 带错误码和参数的错误，Error() 返回当前语言的消息
+type Error struct {
+	Code Code  // 错误码
+	Args []any // 消息参数
+	Err  error // 底层错误，不为空时追加在消息后
+}
+
+// Errorf 创建带错误码的错误
+func Errorf(code Code, args ...any) *Error {
+	return &Error{Code: code, Args: args}
+}
+
+// Wrap 创建包装底层错误的错误，消息格式为 "<本地化消息>: <底层错误>"
+func Wrap(err error, code Code, args ...any) *Error {
+	return &Error{Code: code, Args: args, Err: err}
+}
+
+func (e *Error) Error() string {
+	msg := T(e.Code, e.Args...)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// CodeOf 返回错误链中第一个带错误码的错误的错误码，没有时返回空字符串
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
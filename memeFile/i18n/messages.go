@@ -0,0 +1,434 @@
+package i18n
+
+// 通用错误码
+const (
+	ErrUnsupportedLanguage Code = "unsupported_language"
+	ErrConfigDir           Code = "config_dir_failed"
+	ErrAppDataDir          Code = "app_data_dir_failed"
+	ErrCreateDir           Code = "create_dir_failed"
+)
+
+// 文件和文件夹操作错误码
+const (
+	ErrTabRequired          Code = "tab_required"
+	ErrFolderRequired       Code = "folder_required"
+	ErrRootRequired         Code = "root_required"
+	ErrFileListRequired     Code = "file_list_required"
+	ErrFolderListRequired   Code = "folder_list_required"
+	ErrOldNameRequired      Code = "old_name_required"
+	ErrNewNameRequired      Code = "new_name_required"
+	ErrFolderNotFound       Code = "folder_not_found"
+	ErrRootNotFound         Code = "root_not_found"
+	ErrFileNotFound         Code = "file_not_found"
+	ErrSourceNotFound       Code = "source_not_found"
+	ErrFileExists           Code = "file_exists"
+	ErrRenameFile           Code = "rename_file_failed"
+	ErrRenameFolder         Code = "rename_folder_failed"
+	ErrDeleteFile           Code = "delete_file_failed"
+	ErrStickerSetNotFound   Code = "sticker_set_folder_not_found"
+	ErrDeleteStickerSet     Code = "delete_sticker_set_failed"
+	ErrOpenFile             Code = "open_file_failed"
+	ErrUnsupportedOrderMode Code = "unsupported_order_mode"
+	ErrReadFile             Code = "read_file_failed"
+	ErrRenameJournal        Code = "rename_journal_failed"
+	ErrRenameRollback       Code = "rename_rollback_incomplete"
+	ErrRestoreFile          Code = "restore_file_failed"
+	ErrNothingToUndo        Code = "nothing_to_undo"
+	ErrNothingToRedo        Code = "nothing_to_redo"
+	ErrUnknownHistory       Code = "unknown_history_kind"
+	ErrIncompleteHistory    Code = "incomplete_history"
+	ErrSaveTrash            Code = "save_trash_failed"
+	ErrTrashItemNotFound    Code = "trash_item_not_found"
+	ErrInvalidName          Code = "invalid_name"
+	ErrPathOutsideRoot      Code = "path_outside_root"
+	ErrRootProtected        Code = "root_protected"
+	ErrUnsupportedConflict  Code = "unsupported_conflict_policy"
+	ErrSameFolder           Code = "same_folder"
+	ErrMoveFile             Code = "move_file_failed"
+	ErrCopyFile             Code = "copy_file_failed"
+	ErrTransferFailed       Code = "transfer_failed"
+	ErrDeleteFolder         Code = "delete_folder_failed"
+	ErrSaveCategoryMeta     Code = "save_category_meta_failed"
+	ErrInvalidIcon          Code = "invalid_icon"
+	ErrInvalidColor         Code = "invalid_color"
+	ErrImportFailed         Code = "import_failed"
+)
+
+// 设置和转换配置错误码
+const (
+	ErrSaveSettings           Code = "save_settings_failed"
+	ErrProfileNotFound        Code = "profile_not_found"
+	ErrPresetProfileReadonly  Code = "preset_profile_readonly"
+	ErrPresetProfileUndeleted Code = "preset_profile_undeletable"
+	ErrProfileIDRequired      Code = "profile_id_required"
+	ErrMaxSizeRange           Code = "max_size_out_of_range"
+	ErrStaticSizeRange        Code = "static_size_out_of_range"
+	ErrFPSRange               Code = "fps_out_of_range"
+	ErrEmojiSizeRange         Code = "emoji_size_out_of_range"
+	ErrFrameCapNegative       Code = "frame_cap_negative"
+	ErrColorsRange            Code = "colors_out_of_range"
+	ErrUnsupportedFormat      Code = "unsupported_format"
+	ErrUnsupportedQuantizer   Code = "unsupported_quantizer"
+	ErrUnsupportedScope       Code = "unsupported_palette_scope"
+	ErrAlphaThresholdRange    Code = "alpha_threshold_out_of_range"
+	ErrTrashRetentionRange    Code = "trash_retention_out_of_range"
+	ErrInvalidMatte           Code = "invalid_matte"
+)
+
+// 下载队列错误码
+const (
+	ErrNoStickerSets       Code = "no_sticker_sets"
+	ErrJobNotFound         Code = "job_not_found"
+	ErrJobNotCancellable   Code = "job_not_cancellable"
+	ErrJobNotRetryable     Code = "job_not_retryable"
+	ErrConcurrencyRange    Code = "concurrency_out_of_range"
+	ErrStickerSetRequired  Code = "sticker_set_required"
+	ErrStickerListRequired Code = "sticker_list_required"
+	ErrStickersNotInSet    Code = "stickers_not_in_set"
+	ErrAllStickersFailed   Code = "all_stickers_failed"
+)
+
+// Bot Token 错误码
+const (
+	ErrGenerateKey      Code = "generate_key_failed"
+	ErrSaveKey          Code = "save_key_failed"
+	ErrReadKey          Code = "read_key_failed"
+	ErrReadTokenFile    Code = "read_token_file_failed"
+	ErrTokenFileCorrupt Code = "token_file_corrupt"
+	ErrTokenDecrypt     Code = "token_decrypt_failed"
+	ErrRandom           Code = "random_failed"
+	ErrSaveToken        Code = "save_token_failed"
+	ErrDeleteTokenFile  Code = "delete_token_file_failed"
+	ErrBotTokenRequired Code = "bot_token_required"
+	ErrBotTokenFormat   Code = "bot_token_format"
+	ErrSaveBotToken     Code = "save_bot_token_failed"
+	ErrBotTokenRejected Code = "bot_token_rejected"
+)
+
+// Telegram API 和网络错误码
+const (
+	ErrFetchStickerSet     Code = "fetch_sticker_set_failed"
+	ErrFetchCustomEmoji    Code = "fetch_custom_emoji_failed"
+	ErrHTTPStatus          Code = "http_status"
+	ErrAPIStatus           Code = "api_http_status"
+	ErrParseResponse       Code = "parse_response_failed"
+	ErrAPI                 Code = "api_error"
+	ErrGetFileInfo         Code = "get_file_info_failed"
+	ErrParseFileInfo       Code = "parse_file_info_failed"
+	ErrDownloadFile        Code = "download_file_failed"
+	ErrReadFileContent     Code = "read_file_content_failed"
+	ErrEmojiIDsRequired    Code = "emoji_ids_required"
+	ErrCustomEmojiNotFound Code = "custom_emoji_not_found"
+	ErrProxyRequired       Code = "proxy_required"
+	ErrProxyInvalid        Code = "proxy_invalid"
+	ErrProxyScheme         Code = "proxy_unsupported_scheme"
+	ErrProxyHost           Code = "proxy_missing_host"
+	ErrProxyPort           Code = "proxy_invalid_port"
+	ErrProxyPath           Code = "proxy_has_path"
+)
+
+// 贴纸引用错误码
+const (
+	ErrStickerRefRequired Code = "sticker_ref_required"
+	ErrInvalidLink        Code = "invalid_link"
+	ErrUnsupportedTgLink  Code = "unsupported_tg_link"
+	ErrNotStickerLink     Code = "not_sticker_link"
+	ErrInvalidSetName     Code = "invalid_set_name"
+)
+
+// 贴纸转换和清单错误码
+const (
+	ErrUnsupportedSource Code = "unsupported_source"
+	ErrConvertWebP       Code = "convert_webp_failed"
+	ErrConvertWebM       Code = "convert_webm_failed"
+	ErrGzipReader        Code = "gzip_reader_failed"
+	ErrReadLottie        Code = "read_lottie_failed"
+	ErrLoadLottie        Code = "load_lottie_failed"
+	ErrNoFrames          Code = "no_frames"
+	ErrCreateOutput      Code = "create_output_failed"
+	ErrEncodeGIF         Code = "encode_gif_failed"
+	ErrEncodeAPNG        Code = "encode_apng_failed"
+	ErrEncodeWebP        Code = "encode_webp_failed"
+	ErrCreateSourceDir   Code = "create_source_dir_failed"
+	ErrSourceDirNotFound Code = "source_dir_not_found"
+	ErrReadSourceDir     Code = "read_source_dir_failed"
+	ErrSourceDirEmpty    Code = "source_dir_empty"
+	ErrThumbCacheDir     Code = "thumb_cache_dir_failed"
+	ErrWriteThumb        Code = "write_thumb_failed"
+	ErrParseManifest     Code = "parse_manifest_failed"
+)
+
+// catalogue 消息目录：语言 -> 错误码 -> 格式字符串
+var catalogue = map[string]map[Code]string{
+	LangZhCN: {
+		ErrUnsupportedLanguage: "不支持的语言: %s",
+		ErrConfigDir:           "获取用户配置目录失败",
+		ErrAppDataDir:          "创建应用配置目录失败",
+		ErrCreateDir:           "创建目录失败",
+
+		ErrTabRequired:          "tab名称不能为空",
+		ErrFolderRequired:       "文件夹路径不能为空",
+		ErrRootRequired:         "根路径不能为空",
+		ErrFileListRequired:     "文件列表不能为空",
+		ErrFolderListRequired:   "文件夹列表不能为空",
+		ErrOldNameRequired:      "原文件名不能为空",
+		ErrNewNameRequired:      "新文件名不能为空",
+		ErrFolderNotFound:       "文件夹不存在: %s",
+		ErrRootNotFound:         "根目录不存在: %s",
+		ErrFileNotFound:         "文件不存在: %s",
+		ErrSourceNotFound:       "原文件不存在: %s",
+		ErrFileExists:           "文件名已存在: %s",
+		ErrRenameFile:           "重命名文件失败 %s -> %s",
+		ErrRenameFolder:         "重命名文件夹失败 %s -> %s",
+		ErrDeleteFile:           "删除文件失败",
+		ErrStickerSetNotFound:   "贴纸集合文件夹不存在: %s",
+		ErrDeleteStickerSet:     "删除贴纸集合失败",
+		ErrOpenFile:             "打开文件失败: %s",
+		ErrUnsupportedOrderMode: "不支持的排序方式: %s",
+		ErrReadFile:             "读取文件失败: %s",
+		ErrRenameJournal:        "写入重命名日志失败",
+		ErrRenameRollback:       "重命名失败且未能完全回滚，将在下次启动时恢复",
+		ErrRestoreFile:          "恢复文件失败: %s",
+		ErrNothingToUndo:        "没有可撤销的操作",
+		ErrNothingToRedo:        "没有可重做的操作",
+		ErrUnknownHistory:       "未知的操作类型: %s",
+		ErrIncompleteHistory:    "操作记录不完整，无法撤销或重做: %s",
+		ErrSaveTrash:            "保存回收站索引失败",
+		ErrTrashItemNotFound:    "回收站中不存在该条目: %s",
+		ErrInvalidName:          "名称不合法: %q",
+		ErrPathOutsideRoot:      "路径不在根目录内: %s",
+		ErrRootProtected:        "不能操作根目录本身: %s",
+		ErrUnsupportedConflict:  "不支持的重名处理方式: %s",
+		ErrSameFolder:           "源文件夹和目标文件夹相同: %s",
+		ErrMoveFile:             "移动文件失败: %s",
+		ErrCopyFile:             "复制文件失败: %s",
+		ErrTransferFailed:       "%d 个文件全部处理失败: %s",
+		ErrDeleteFolder:         "删除文件夹失败",
+		ErrSaveCategoryMeta:     "保存分类信息失败",
+		ErrInvalidIcon:          "图标必须是分类文件夹中的图片: %s",
+		ErrInvalidColor:         "颜色格式错误: %s",
+		ErrImportFailed:         "%d 个文件全部导入失败: %s",
+
+		ErrSaveSettings:           "保存设置失败",
+		ErrProfileNotFound:        "转换配置不存在: %s",
+		ErrPresetProfileReadonly:  "不能修改预设配置: %s",
+		ErrPresetProfileUndeleted: "不能删除预设配置: %s",
+		ErrProfileIDRequired:      "配置ID不能为空",
+		ErrMaxSizeRange:           "最大尺寸超出范围: %d",
+		ErrStaticSizeRange:        "静态贴纸尺寸超出范围: %d",
+		ErrFPSRange:               "帧率超出范围: %.2f",
+		ErrEmojiSizeRange:         "自定义表情尺寸超出范围: %d",
+		ErrFrameCapNegative:       "最大帧数不能为负数",
+		ErrColorsRange:            "颜色数量超出范围: %d",
+		ErrUnsupportedFormat:      "不支持的输出格式: %s",
+		ErrUnsupportedQuantizer:   "不支持的调色板生成方式: %s",
+		ErrUnsupportedScope:       "不支持的调色板作用范围: %s",
+		ErrAlphaThresholdRange:    "透明度阈值超出范围: %d",
+		ErrTrashRetentionRange:    "回收站保留天数超出范围: %d",
+		ErrInvalidMatte:           "背景色格式错误: %s",
+
+		ErrNoStickerSets:       "未找到有效的贴纸集合",
+		ErrJobNotFound:         "下载任务不存在: %s",
+		ErrJobNotCancellable:   "只能取消等待中的任务: %s",
+		ErrJobNotRetryable:     "只能重试失败或已取消的任务: %s",
+		ErrConcurrencyRange:    "并发数超出范围: %d",
+		ErrStickerSetRequired:  "贴纸集合名称不能为空",
+		ErrStickerListRequired: "贴纸列表不能为空",
+		ErrStickersNotInSet:    "贴纸集合中未找到选择的贴纸: %s",
+		ErrAllStickersFailed:   "全部 %d 个贴纸处理失败: %s",
+
+		ErrGenerateKey:      "生成密钥失败",
+		ErrSaveKey:          "保存密钥失败",
+		ErrReadKey:          "读取密钥失败",
+		ErrReadTokenFile:    "读取 Token 文件失败",
+		ErrTokenFileCorrupt: "Token 文件已损坏",
+		ErrTokenDecrypt:     "无法解密 Token 文件，请重新设置 Bot Token",
+		ErrRandom:           "生成随机数失败",
+		ErrSaveToken:        "保存 Token 失败",
+		ErrDeleteTokenFile:  "删除 Token 文件失败",
+		ErrBotTokenRequired: "请先在设置中配置 Telegram Bot Token",
+		ErrBotTokenFormat:   "Bot Token 格式不正确，应为 \"数字:字符串\" 格式",
+		ErrSaveBotToken:     "保存 Bot Token 失败: %s",
+		ErrBotTokenRejected: "可以访问 Telegram API，但 Bot Token 无效",
+
+		ErrFetchStickerSet:     "获取贴纸集合失败",
+		ErrFetchCustomEmoji:    "获取自定义表情失败",
+		ErrHTTPStatus:          "HTTP %d: %s",
+		ErrAPIStatus:           "Telegram API 返回 HTTP %d",
+		ErrParseResponse:       "解析响应失败",
+		ErrAPI:                 "API 错误: %s",
+		ErrGetFileInfo:         "获取文件信息失败",
+		ErrParseFileInfo:       "解析文件信息失败",
+		ErrDownloadFile:        "下载文件失败",
+		ErrReadFileContent:     "读取文件内容失败",
+		ErrEmojiIDsRequired:    "表情ID列表不能为空",
+		ErrCustomEmojiNotFound: "未找到任何自定义表情",
+		ErrProxyRequired:       "代理地址不能为空",
+		ErrProxyInvalid:        "代理地址格式错误: %s",
+		ErrProxyScheme:         "不支持的代理协议 %q，仅支持 http、https、socks5、socks5h",
+		ErrProxyHost:           "代理地址缺少主机名: %s",
+		ErrProxyPort:           "代理端口无效: %s",
+		ErrProxyPath:           "代理地址不能包含路径或参数: %s",
+
+		ErrStickerRefRequired: "贴纸集合引用不能为空",
+		ErrInvalidLink:        "无法解析链接: %s",
+		ErrUnsupportedTgLink:  "不支持的 tg 链接: %s",
+		ErrNotStickerLink:     "不是 Telegram 贴纸链接: %s",
+		ErrInvalidSetName:     "贴纸集合名称无效: %q",
+
+		ErrUnsupportedSource: "不支持的源文件类型: %s",
+		ErrConvertWebP:       "WebP 转 PNG 失败: %v, 错误: %s",
+		ErrConvertWebM:       "WebM 转换失败: %v, 错误: %s",
+		ErrGzipReader:        "无法创建 GZIP 读取器",
+		ErrReadLottie:        "无法读取解压后的 JSON 数据",
+		ErrLoadLottie:        "无法从 JSON 数据加载 Lottie 动画",
+		ErrNoFrames:          "动画不包含任何帧",
+		ErrCreateOutput:      "无法创建输出文件 '%s'",
+		ErrEncodeGIF:         "编码 GIF 失败",
+		ErrEncodeAPNG:        "编码 APNG 失败",
+		ErrEncodeWebP:        "编码 WebP 失败: %v, 错误: %s",
+		ErrCreateSourceDir:   "创建源文件目录失败",
+		ErrSourceDirNotFound: "未找到源文件目录: %s",
+		ErrReadSourceDir:     "读取源文件目录失败",
+		ErrSourceDirEmpty:    "源文件目录为空: %s",
+		ErrThumbCacheDir:     "创建缩略图缓存目录失败",
+		ErrWriteThumb:        "写入缩略图失败",
+		ErrParseManifest:     "解析贴纸清单失败",
+	},
+
+	LangEn: {
+		ErrUnsupportedLanguage: "unsupported language: %s",
+		ErrConfigDir:           "failed to get the user config directory",
+		ErrAppDataDir:          "failed to create the app config directory",
+		ErrCreateDir:           "failed to create directory",
+
+		ErrTabRequired:          "tab name is required",
+		ErrFolderRequired:       "folder path is required",
+		ErrRootRequired:         "root path is required",
+		ErrFileListRequired:     "file list is required",
+		ErrFolderListRequired:   "folder list is required",
+		ErrOldNameRequired:      "original file name is required",
+		ErrNewNameRequired:      "new file name is required",
+		ErrFolderNotFound:       "folder not found: %s",
+		ErrRootNotFound:         "root directory not found: %s",
+		ErrFileNotFound:         "file not found: %s",
+		ErrSourceNotFound:       "original file not found: %s",
+		ErrFileExists:           "file name already exists: %s",
+		ErrRenameFile:           "failed to rename file %s -> %s",
+		ErrRenameFolder:         "failed to rename folder %s -> %s",
+		ErrDeleteFile:           "failed to delete file",
+		ErrStickerSetNotFound:   "sticker set folder not found: %s",
+		ErrDeleteStickerSet:     "failed to delete sticker set",
+		ErrOpenFile:             "failed to open file: %s",
+		ErrUnsupportedOrderMode: "unsupported order mode: %s",
+		ErrReadFile:             "failed to read file: %s",
+		ErrRenameJournal:        "failed to write rename journal",
+		ErrRenameRollback:       "rename failed and could not be fully rolled back; it will be recovered on next start",
+		ErrRestoreFile:          "failed to restore file: %s",
+		ErrNothingToUndo:        "nothing to undo",
+		ErrNothingToRedo:        "nothing to redo",
+		ErrUnknownHistory:       "unknown operation kind: %s",
+		ErrIncompleteHistory:    "operation record is incomplete and cannot be undone or redone: %s",
+		ErrSaveTrash:            "failed to save recycle bin index",
+		ErrTrashItemNotFound:    "item not found in recycle bin: %s",
+		ErrInvalidName:          "invalid name: %q",
+		ErrPathOutsideRoot:      "path is outside the root folder: %s",
+		ErrRootProtected:        "cannot operate on the root folder itself: %s",
+		ErrUnsupportedConflict:  "unsupported conflict policy: %s",
+		ErrSameFolder:           "source and target folders are the same: %s",
+		ErrMoveFile:             "failed to move file: %s",
+		ErrCopyFile:             "failed to copy file: %s",
+		ErrTransferFailed:       "all %d files failed: %s",
+		ErrDeleteFolder:         "failed to delete folder",
+		ErrSaveCategoryMeta:     "failed to save category info",
+		ErrInvalidIcon:          "icon must be an image in the category folder: %s",
+		ErrInvalidColor:         "invalid colour: %s",
+		ErrImportFailed:         "all %d files failed to import: %s",
+
+		ErrSaveSettings:           "failed to save settings",
+		ErrProfileNotFound:        "conversion profile not found: %s",
+		ErrPresetProfileReadonly:  "preset profiles cannot be modified: %s",
+		ErrPresetProfileUndeleted: "preset profiles cannot be deleted: %s",
+		ErrProfileIDRequired:      "profile ID is required",
+		ErrMaxSizeRange:           "max size out of range: %d",
+		ErrStaticSizeRange:        "static sticker size out of range: %d",
+		ErrFPSRange:               "frame rate out of range: %.2f",
+		ErrEmojiSizeRange:         "custom emoji size out of range: %d",
+		ErrFrameCapNegative:       "frame cap cannot be negative",
+		ErrColorsRange:            "colour count out of range: %d",
+		ErrUnsupportedFormat:      "unsupported output format: %s",
+		ErrUnsupportedQuantizer:   "unsupported quantizer: %s",
+		ErrUnsupportedScope:       "unsupported palette scope: %s",
+		ErrAlphaThresholdRange:    "alpha threshold out of range: %d",
+		ErrTrashRetentionRange:    "recycle bin retention out of range: %d",
+		ErrInvalidMatte:           "invalid matte colour: %s",
+
+		ErrNoStickerSets:       "no valid sticker sets found",
+		ErrJobNotFound:         "download job not found: %s",
+		ErrJobNotCancellable:   "only pending jobs can be cancelled: %s",
+		ErrJobNotRetryable:     "only failed or cancelled jobs can be retried: %s",
+		ErrConcurrencyRange:    "concurrency out of range: %d",
+		ErrStickerSetRequired:  "sticker set name is required",
+		ErrStickerListRequired: "sticker list is required",
+		ErrStickersNotInSet:    "none of the selected stickers were found in the set: %s",
+		ErrAllStickersFailed:   "all %d stickers failed: %s",
+
+		ErrGenerateKey:      "failed to generate key",
+		ErrSaveKey:          "failed to save key",
+		ErrReadKey:          "failed to read key",
+		ErrReadTokenFile:    "failed to read token file",
+		ErrTokenFileCorrupt: "token file is corrupted",
+		ErrTokenDecrypt:     "cannot decrypt the token file, please set the bot token again",
+		ErrRandom:           "failed to generate random bytes",
+		ErrSaveToken:        "failed to save token",
+		ErrDeleteTokenFile:  "failed to delete token file",
+		ErrBotTokenRequired: "please configure a Telegram bot token in settings first",
+		ErrBotTokenFormat:   "invalid bot token, expected the \"digits:string\" format",
+		ErrSaveBotToken:     "failed to save bot token: %s",
+		ErrBotTokenRejected: "Telegram API is reachable, but the bot token is invalid",
+
+		ErrFetchStickerSet:     "failed to fetch sticker set",
+		ErrFetchCustomEmoji:    "failed to fetch custom emoji",
+		ErrHTTPStatus:          "HTTP %d: %s",
+		ErrAPIStatus:           "Telegram API returned HTTP %d",
+		ErrParseResponse:       "failed to parse response",
+		ErrAPI:                 "API error: %s",
+		ErrGetFileInfo:         "failed to get file info",
+		ErrParseFileInfo:       "failed to parse file info",
+		ErrDownloadFile:        "failed to download file",
+		ErrReadFileContent:     "failed to read file content",
+		ErrEmojiIDsRequired:    "emoji ID list is required",
+		ErrCustomEmojiNotFound: "no custom emoji found",
+		ErrProxyRequired:       "proxy URL is required",
+		ErrProxyInvalid:        "invalid proxy URL: %s",
+		ErrProxyScheme:         "unsupported proxy scheme %q, only http, https, socks5 and socks5h are supported",
+		ErrProxyHost:           "proxy URL is missing a host: %s",
+		ErrProxyPort:           "invalid proxy port: %s",
+		ErrProxyPath:           "proxy URL must not contain a path or query: %s",
+
+		ErrStickerRefRequired: "sticker set reference is required",
+		ErrInvalidLink:        "cannot parse link: %s",
+		ErrUnsupportedTgLink:  "unsupported tg link: %s",
+		ErrNotStickerLink:     "not a Telegram sticker link: %s",
+		ErrInvalidSetName:     "invalid sticker set name: %q",
+
+		ErrUnsupportedSource: "unsupported source file type: %s",
+		ErrConvertWebP:       "failed to convert WebP to PNG: %v, output: %s",
+		ErrConvertWebM:       "failed to convert WebM: %v, output: %s",
+		ErrGzipReader:        "cannot create GZIP reader",
+		ErrReadLottie:        "cannot read decompressed JSON data",
+		ErrLoadLottie:        "cannot load Lottie animation from JSON data",
+		ErrNoFrames:          "animation contains no frames",
+		ErrCreateOutput:      "cannot create output file '%s'",
+		ErrEncodeGIF:         "failed to encode GIF",
+		ErrEncodeAPNG:        "failed to encode APNG",
+		ErrEncodeWebP:        "failed to encode WebP: %v, output: %s",
+		ErrCreateSourceDir:   "failed to create source directory",
+		ErrSourceDirNotFound: "source directory not found: %s",
+		ErrReadSourceDir:     "failed to read source directory",
+		ErrSourceDirEmpty:    "source directory is empty: %s",
+		ErrThumbCacheDir:     "failed to create thumbnail cache directory",
+		ErrWriteThumb:        "failed to write thumbnail",
+		ErrParseManifest:     "failed to parse sticker manifest",
+	},
+}
@@ -0,0 +1,209 @@
+package memeFile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 单个文件的导入结果
+const (
+	ImportImported    = "imported"    // 已导入，名称不变
+	ImportRenamed     = "renamed"     // 已导入，名称经过规范化或因重名改名
+	ImportDuplicate   = "duplicate"   // 分类中已有内容相同的图片，跳过
+	ImportUnsupported = "unsupported" // 不是支持的图片格式，跳过
+	ImportFailed      = "failed"      // 失败
+)
+
+// ImportItem 结构体 - 单个文件的导入结果
+type ImportItem struct {
+	Source      string `json:"source"`      // 源文件路径
+	FinalName   string `json:"finalName"`   // 分类文件夹中的文件名，未导入时为空
+	Status      string `json:"status"`      // 导入结果
+	DuplicateOf string `json:"duplicateOf"` // 重复时已有的文件名
+	ErrorCode   string `json:"errorCode"`   // 失败的错误码
+	Error       string `json:"error"`       // 失败原因
+}
+
+// ImportResult 结构体 - 一次导入的结果
+type ImportResult struct {
+	Folder      string       `json:"folder"`      // 目标分类文件夹
+	Items       []ImportItem `json:"items"`       // 每个文件的结果
+	Imported    int          `json:"imported"`    // 导入数量（包括改名导入）
+	Duplicates  int          `json:"duplicates"`  // 重复数量
+	Unsupported int          `json:"unsupported"` // 不支持的文件数量
+	Failed      int          `json:"failed"`      // 失败数量
+}
+
+// add 记录一个文件的导入结果
+func (r *ImportResult) add(item ImportItem) {
+	switch item.Status {
+	case ImportImported, ImportRenamed:
+		r.Imported++
+	case ImportDuplicate:
+		r.Duplicates++
+	case ImportUnsupported:
+		r.Unsupported++
+	case ImportFailed:
+		r.Failed++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// hashFile 计算文件内容的 SHA-256
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// collectImportFiles 展开导入路径，文件夹递归展开为其中的文件，跳过隐藏文件和隐藏文件夹
+func collectImportFiles(paths []string) []string {
+	var files []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			// 不存在的路径也保留，由导入时报告失败
+			files = append(files, path)
+			continue
+		}
+
+		filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.IsDir() {
+				files = append(files, p)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// normalizeImportName 规范化导入的文件名：修正特殊字符等问题（见 FileUtils.SanitizeName），
+// 合并连续空白，扩展名转为小写
+func (m *MemeFile) normalizeImportName(name string) string {
+	name, _ = m.fileUtils.SanitizeName(strings.Join(strings.Fields(name), " "))
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + strings.ToLower(ext)
+}
+
+// ImportFiles 将文件或文件夹（拖放或文件对话框选择的路径）中的图片复制到分类文件夹
+// 按内容哈希跳过分类中已有的图片，规范化文件名，重名时自动改名
+func (m *MemeFile) ImportFiles(paths []string, rootPath string, folderCode string) (*ImportResult, error) {
+	if len(paths) == 0 {
+		return nil, i18n.Errorf(i18n.ErrFileListRequired)
+	}
+	folderPath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return nil, err
+	}
+	if !m.fileUtils.IsDir(folderPath) {
+		return nil, i18n.Errorf(i18n.ErrFolderNotFound, folderCode)
+	}
+
+	// 分类中已有图片的哈希 -> 文件名
+	hashes := make(map[string]string)
+	for _, name := range m.GetImages(folderPath) {
+		hash, err := hashFile(filepath.Join(folderPath, name))
+		if err != nil {
+			continue
+		}
+		hashes[hash] = name
+	}
+
+	result := &ImportResult{Folder: folderCode, Items: []ImportItem{}}
+	for _, src := range collectImportFiles(paths) {
+		result.add(m.importFile(src, folderPath, hashes))
+	}
+
+	// 全部失败时返回第一个错误，部分失败由调用方根据结果提示
+	if result.Imported == 0 && result.Failed > 0 {
+		for _, item := range result.Items {
+			if item.Status == ImportFailed {
+				return result, i18n.Errorf(i18n.ErrImportFailed, result.Failed, item.Error)
+			}
+		}
+	}
+	return result, nil
+}
+
+// importFile 导入单个文件，成功后将哈希加入 hashes，避免同一批次中的重复文件
+func (m *MemeFile) importFile(src string, folderPath string, hashes map[string]string) ImportItem {
+	item := ImportItem{Source: src}
+	fail := func(err error) ImportItem {
+		item.FinalName = ""
+		item.Status = ImportFailed
+		item.ErrorCode = string(i18n.CodeOf(err))
+		item.Error = err.Error()
+		return item
+	}
+
+	if !m.fileUtils.IsFile(src) {
+		return fail(i18n.Errorf(i18n.ErrFileNotFound, src))
+	}
+	if !m.imageUtils.IsImageFile(src) {
+		item.Status = ImportUnsupported
+		return item
+	}
+
+	hash, err := hashFile(src)
+	if err != nil {
+		return fail(i18n.Wrap(err, i18n.ErrReadFile, src))
+	}
+	if existing, ok := hashes[hash]; ok {
+		item.Status = ImportDuplicate
+		item.DuplicateOf = existing
+		return item
+	}
+
+	original := filepath.Base(src)
+	name := m.normalizeImportName(original)
+	if m.fileUtils.PathExists(filepath.Join(folderPath, name)) {
+		name = m.uniqueName(folderPath, name)
+	}
+
+	dst := filepath.Join(folderPath, name)
+	if err := m.fileUtils.Copy(src, dst); err != nil {
+		// 目标已存在时不是本次创建的文件，不能删除
+		if !errors.Is(err, fs.ErrExist) {
+			os.Remove(dst)
+		}
+		return fail(i18n.Wrap(err, i18n.ErrCopyFile, original))
+	}
+
+	hashes[hash] = name
+	item.FinalName = name
+	item.Status = ImportImported
+	if name != original {
+		item.Status = ImportRenamed
+	}
+	return item
+}
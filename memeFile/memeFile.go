@@ -7,7 +7,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"mymeme/memeFile/i18n"
 	"mymeme/memeFile/platform"
 	"mymeme/memeFile/sticker"
 	"mymeme/memeFile/utils"
@@ -20,17 +22,34 @@ type MemeFile struct {
 	ctx        context.Context // Wails应用上下文
 	fileUtils  *utils.FileUtils
 	imageUtils *utils.ImageUtils
-	downloader *sticker.TelegramDownloader
 	clipboard  platform.Clipboard // 跨平台剪贴板实例
+	queue      *downloadQueue     // 贴纸集合下载队列
+	tokens     *tokenStore        // Bot Token 存储
+
+	settings   Settings   // 持久化设置
+	settingsMu sync.Mutex // 保护 settings
+
+	renameMu  sync.Mutex // 同一时间只执行一个批量重命名，保护重命名日志
+	historyMu sync.Mutex // 保护撤销/重做记录
+	trashMu   sync.Mutex // 保护回收站索引
 }
 
 // NewMemeFile 创建新的MemeFile实例
 func NewMemeFile() *MemeFile {
-	return &MemeFile{
+	// 所有日志输出前隐藏 Bot Token
+	log.SetOutput(sticker.NewRedactWriter(log.Writer()))
+
+	m := &MemeFile{
 		fileUtils:  utils.NewFileUtils(),
 		imageUtils: utils.NewImageUtils(),
 		clipboard:  platform.NewClipboard(),
 	}
+	m.tokens = newTokenStore(m)
+	m.loadSettings()
+	m.recoverRenames()
+	m.purgeTrash()
+	m.queue = newDownloadQueue(m, m.settings.DownloadConcurrency)
+	return m
 }
 
 // SetContext 设置Wails应用上下文
@@ -87,11 +106,13 @@ func (m *MemeFile) GetImages(path string) []string {
 
 // MemeInfo 结构体 - 存储meme信息
 type MemeInfo struct {
-	Name       string   `json:"name"`       // meme名称
-	Code       string   `json:"code"`       // meme唯一标识
-	ParentPath string   `json:"parentPath"` // 父目录路径
-	Icon       string   `json:"icon"`       // 图标文件名
-	Memes      []string `json:"memes"`      // 图片文件列表
+	Name        string   `json:"name"`        // meme名称（显示名称）
+	Code        string   `json:"code"`        // meme唯一标识
+	ParentPath  string   `json:"parentPath"`  // 父目录路径
+	Icon        string   `json:"icon"`        // 图标文件名
+	Memes       []string `json:"memes"`       // 图片文件列表
+	Description string   `json:"description"` // 分类描述
+	Color       string   `json:"color"`       // 标签颜色
 }
 
 // GenerateAllMemePath 生成所有meme的信息
@@ -114,30 +135,41 @@ func (m *MemeFile) GenerateAllMemePath(rootPath string) []MemeInfo {
 		return nil
 	}
 
-	// 遍历根目录下的每个条目
+	// 只处理子文件夹
+	var dirNames []string
 	for _, entry := range entries {
-		// 只处理子文件夹
-		if !entry.IsDir() {
-			continue
+		if entry.IsDir() {
+			dirNames = append(dirNames, entry.Name())
 		}
+	}
 
-		dirName := entry.Name()
+	// 按顺序清单排列分类，不在清单中的按名称排在最后
+	dirNames = sortByOrder(dirNames, m.readOrder(rootPath))
+
+	// 遍历根目录下的每个子文件夹
+	for _, dirName := range dirNames {
 		dirPath := filepath.Join(rootPath, dirName)
 
-		// 获取子文件夹内的所有图片文件名
-		imageNames := m.GetImages(dirPath)
+		// 获取子文件夹内的所有图片文件名，按顺序清单排列
+		// 没有图片的分类（如刚创建的分类）也显示，以便向其中添加表情；隐藏文件夹除外
+		imageNames := sortByOrder(m.GetImages(dirPath), m.readOrder(dirPath))
 		if len(imageNames) == 0 {
-			log.Printf("跳过无图片的文件夹: %s", dirName)
-			continue
+			if strings.HasPrefix(dirName, ".") {
+				continue
+			}
+			imageNames = []string{}
 		}
 
-		// 创建meme信息
+		// 创建meme信息，显示名称和图标可由分类信息文件自定义
+		meta := m.readCategoryMeta(dirPath)
 		memeInfo := MemeInfo{
-			Name:       dirName,       // 文件夹名作为名称
-			Code:       dirName,       // 文件夹名作为唯一标识
-			ParentPath: dirPath,       // 父文件夹路径
-			Icon:       imageNames[0], // 使用第一张图片作为图标
-			Memes:      imageNames,    // 图片文件列表
+			Name:        m.categoryDisplayName(meta, dirName), // 显示名称
+			Code:        dirName,                              // 文件夹名作为唯一标识
+			ParentPath:  dirPath,                              // 父文件夹路径
+			Icon:        categoryIcon(meta, imageNames),       // 自定义图标或第一张图片，没有图片时为空
+			Memes:       imageNames,                           // 图片文件列表
+			Description: meta.Description,
+			Color:       meta.Color,
 		}
 
 		loadedMemes = append(loadedMemes, memeInfo)
@@ -162,166 +194,217 @@ func (m *MemeFile) GetFilesFromClipboard() ([]string, error) {
 
 func (m *MemeFile) RenameFilesInOrder(tabName string, folderPath string, fileNames []string) error {
 	if tabName == "" {
-		return fmt.Errorf("tab名称不能为空")
+		return i18n.Errorf(i18n.ErrTabRequired)
 	}
 	if folderPath == "" {
-		return fmt.Errorf("文件夹路径不能为空")
+		return i18n.Errorf(i18n.ErrFolderRequired)
 	}
 	if len(fileNames) == 0 {
-		return fmt.Errorf("文件列表不能为空")
+		return i18n.Errorf(i18n.ErrFileListRequired)
+	}
+	if err := validateName(tabName); err != nil {
+		return err
+	}
+	if err := validateNames(fileNames); err != nil {
+		return err
 	}
 
 	if !m.fileUtils.PathExists(folderPath) {
-		return fmt.Errorf("文件夹不存在: %s", folderPath)
+		return i18n.Errorf(i18n.ErrFolderNotFound, folderPath)
 	}
 
-	// 创建临时文件名映射，避免重命名冲突
-	tempFileNames := make(map[string]string)
-	finalFileNames := make(map[string]string)
+	// 清单模式只保存顺序，不修改文件名
+	if m.orderMode() == OrderModeManifest {
+		return m.SaveMemeOrder(folderPath, fileNames)
+	}
 
-	// 将所有文件重命名为临时名称
+	// 生成每个文件的临时文件名和最终文件名，通过临时文件名避免重命名冲突
+	var steps []renameStep
+	seen := make(map[string]bool)
 	for i, fileName := range fileNames {
-		if fileName == "" {
+		if fileName == "" || seen[fileName] {
 			continue
 		}
+		seen[fileName] = true
 
-		oldPath := filepath.Join(folderPath, fileName)
-
-		if !m.fileUtils.PathExists(oldPath) {
+		if !m.fileUtils.PathExists(filepath.Join(folderPath, fileName)) {
 			continue
 		}
 
 		ext := m.fileUtils.GetFileExt(fileName)
+		steps = append(steps, renameStep{
+			Old:   fileName,
+			Temp:  fmt.Sprintf("temp_%d_%s%s", i, tabName, ext),
+			Final: fmt.Sprintf("%s_%02d%s", tabName, i+1, ext),
+		})
+	}
 
-		// 生成临时文件名
-		tempFileName := fmt.Sprintf("temp_%d_%s%s", i, tabName, ext)
-		tempPath := filepath.Join(folderPath, tempFileName)
-
-		// 生成最终文件名
-		finalFileName := fmt.Sprintf("%s_%02d%s", tabName, i+1, ext)
-
-		// 重命名为临时文件名
-		if err := os.Rename(oldPath, tempPath); err != nil {
-			return fmt.Errorf("重命名文件失败 %s -> %s: %v", oldPath, tempPath, err)
-		}
+	if len(steps) == 0 {
+		return nil
+	}
 
-		tempFileNames[tempFileName] = fileName
-		finalFileNames[tempFileName] = finalFileName
+	before := m.readOrder(folderPath)
+	if err := m.renameBatch(folderPath, steps, i18n.ErrRenameFile); err != nil {
+		return err
 	}
 
-	// 将临时文件名重命名为最终文件名
-	for tempFileName, finalFileName := range finalFileNames {
-		tempPath := filepath.Join(folderPath, tempFileName)
-		finalPath := filepath.Join(folderPath, finalFileName)
+	// 文件名已体现顺序，旧的顺序清单不再适用
+	m.removeOrder(folderPath)
 
-		if err := os.Rename(tempPath, finalPath); err != nil {
-			// 尝试回滚已重命名的文件
-			if originalName, exists := tempFileNames[tempFileName]; exists {
-				originalPath := filepath.Join(folderPath, originalName)
-				os.Rename(tempPath, originalPath)
-			}
+	entry := newHistoryEntry(HistoryBatchRename, folderPath, filepath.Base(folderPath))
+	entry.Steps = steps
+	entry.Before = before
+	m.recordHistory(entry)
+	return nil
+}
 
-			return fmt.Errorf("重命名文件失败 %s -> %s: %v", tempFileName, finalFileName, err)
-		}
+func (m *MemeFile) RenameFile(folderPath string, oldFileName string, newFileName string) error {
+	if err := m.renameFile(folderPath, oldFileName, newFileName); err != nil {
+		return err
 	}
 
+	entry := newHistoryEntry(HistoryRename, folderPath, historyLabel(folderPath, oldFileName))
+	entry.Steps = []renameStep{{Old: oldFileName, Final: newFileName}}
+	m.recordHistory(entry)
 	return nil
 }
 
-func (m *MemeFile) RenameFile(folderPath string, oldFileName string, newFileName string) error {
+// renameFile 重命名单个文件并同步顺序清单，不记录历史
+func (m *MemeFile) renameFile(folderPath string, oldFileName string, newFileName string) error {
 	if folderPath == "" {
-		return fmt.Errorf("文件夹路径不能为空")
+		return i18n.Errorf(i18n.ErrFolderRequired)
 	}
 	if oldFileName == "" {
-		return fmt.Errorf("原文件名不能为空")
+		return i18n.Errorf(i18n.ErrOldNameRequired)
 	}
 	if newFileName == "" {
-		return fmt.Errorf("新文件名不能为空")
+		return i18n.Errorf(i18n.ErrNewNameRequired)
 	}
 
 	if !m.fileUtils.PathExists(folderPath) {
-		return fmt.Errorf("文件夹不存在: %s", folderPath)
+		return i18n.Errorf(i18n.ErrFolderNotFound, folderPath)
 	}
 
-	oldPath := filepath.Join(folderPath, oldFileName)
-	newPath := filepath.Join(folderPath, newFileName)
+	oldPath, err := resolvePath(folderPath, oldFileName)
+	if err != nil {
+		return err
+	}
+	newPath, err := resolvePath(folderPath, newFileName)
+	if err != nil {
+		return err
+	}
 
 	if !m.fileUtils.PathExists(oldPath) {
-		return fmt.Errorf("原文件不存在: %s", oldFileName)
+		return i18n.Errorf(i18n.ErrSourceNotFound, oldFileName)
 	}
 
+	// 只修改大小写或 Unicode 规范形式时，在不区分大小写或规范化名称的文件系统上新名称指向同一个文件
+	sameFile := false
 	if m.fileUtils.PathExists(newPath) {
-		return fmt.Errorf("文件名已存在: %s", newFileName)
+		oldInfo, oldErr := os.Stat(oldPath)
+		newInfo, newErr := os.Stat(newPath)
+		if oldErr != nil || newErr != nil || !os.SameFile(oldInfo, newInfo) {
+			return i18n.Errorf(i18n.ErrFileExists, newFileName)
+		}
+		sameFile = true
 	}
 
-	if err := os.Rename(oldPath, newPath); err != nil {
-		return fmt.Errorf("重命名文件失败 %s -> %s: %v", oldFileName, newFileName, err)
+	if sameFile {
+		// 通过临时名称重命名，确保文件系统记录新的名称
+		tempName := "." + oldFileName + ".renaming"
+		if m.fileUtils.PathExists(filepath.Join(folderPath, tempName)) {
+			tempName = m.uniqueName(folderPath, tempName)
+		}
+		tempPath := filepath.Join(folderPath, tempName)
+		if err := os.Rename(oldPath, tempPath); err != nil {
+			return i18n.Wrap(err, i18n.ErrRenameFile, oldFileName, newFileName)
+		}
+		// 原名称移走后新名称仍然存在，说明是另一个目录项（如硬链接），不能覆盖
+		if m.fileUtils.PathExists(newPath) {
+			if restoreErr := os.Rename(tempPath, oldPath); restoreErr != nil {
+				log.Printf("恢复原文件名失败 %s: %v", oldPath, restoreErr)
+			}
+			return i18n.Errorf(i18n.ErrFileExists, newFileName)
+		}
+		if err := os.Rename(tempPath, newPath); err != nil {
+			if restoreErr := os.Rename(tempPath, oldPath); restoreErr != nil {
+				log.Printf("恢复原文件名失败 %s: %v", oldPath, restoreErr)
+			}
+			return i18n.Wrap(err, i18n.ErrRenameFile, oldFileName, newFileName)
+		}
+	} else if err := os.Rename(oldPath, newPath); err != nil {
+		return i18n.Wrap(err, i18n.ErrRenameFile, oldFileName, newFileName)
 	}
 
+	m.renameInOrder(folderPath, oldFileName, newFileName)
+	renames := map[string]string{oldFileName: newFileName}
+	m.renameMetaIcon(folderPath, renames)
+	if err := sticker.RenameManifestOutputs(folderPath, renames); err != nil {
+		log.Printf("更新贴纸清单失败 %s: %v", folderPath, err)
+	}
 	return nil
 }
 
 func (m *MemeFile) RenameFoldersInOrder(rootPath string, folderNames []string) error {
 	if rootPath == "" {
-		return fmt.Errorf("根路径不能为空")
+		return i18n.Errorf(i18n.ErrRootRequired)
 	}
 	if len(folderNames) == 0 {
-		return fmt.Errorf("文件夹列表不能为空")
+		return i18n.Errorf(i18n.ErrFolderListRequired)
+	}
+	if err := validateNames(folderNames); err != nil {
+		return err
 	}
 
 	if !m.fileUtils.PathExists(rootPath) {
-		return fmt.Errorf("根目录不存在: %s", rootPath)
+		return i18n.Errorf(i18n.ErrRootNotFound, rootPath)
 	}
 
-	// 创建临时文件夹名映射，避免重命名冲突
-	tempFolderNames := make(map[string]string)
-	finalFolderNames := make(map[string]string)
+	// 清单模式只保存顺序，不修改文件夹名
+	if m.orderMode() == OrderModeManifest {
+		return m.SaveCategoryOrder(rootPath, folderNames)
+	}
 
+	// 生成每个文件夹的临时名称和最终名称，通过临时名称避免重命名冲突
+	var steps []renameStep
+	seen := make(map[string]bool)
 	for i, folderName := range folderNames {
-		if folderName == "" {
+		if folderName == "" || seen[folderName] {
 			continue
 		}
-
-		oldPath := filepath.Join(rootPath, folderName)
+		seen[folderName] = true
 
 		// 检查原文件夹是否存在
-		if !m.fileUtils.PathExists(oldPath) {
+		if !m.fileUtils.PathExists(filepath.Join(rootPath, folderName)) {
 			continue
 		}
 
-		// 生成临时文件夹名
-		tempFolderName := fmt.Sprintf("temp_%d_%s", i, folderName)
-		tempPath := filepath.Join(rootPath, tempFolderName)
-
 		// 生成最终文件夹名 - 如果已经是 序号_ 开头，则去掉序号部分
 		cleanFolderName := m.cleanFolderName(folderName)
-		finalFolderName := fmt.Sprintf("%02d_%s", i+1, cleanFolderName)
-
-		// 重命名为临时文件夹名
-		if err := os.Rename(oldPath, tempPath); err != nil {
-			return fmt.Errorf("重命名文件夹失败 %s -> %s: %v", oldPath, tempPath, err)
-		}
-
-		tempFolderNames[tempFolderName] = folderName
-		finalFolderNames[tempFolderName] = finalFolderName
+		steps = append(steps, renameStep{
+			Old:   folderName,
+			Temp:  fmt.Sprintf("temp_%d_%s", i, folderName),
+			Final: fmt.Sprintf("%02d_%s", i+1, cleanFolderName),
+		})
 	}
 
-	// 将临时文件夹名重命名为最终文件夹名
-	for tempFolderName, finalFolderName := range finalFolderNames {
-		tempPath := filepath.Join(rootPath, tempFolderName)
-		finalPath := filepath.Join(rootPath, finalFolderName)
-
-		if err := os.Rename(tempPath, finalPath); err != nil {
-			// 如果失败，回滚已重命名的文件夹
-			if originalName, exists := tempFolderNames[tempFolderName]; exists {
-				originalPath := filepath.Join(rootPath, originalName)
-				os.Rename(tempPath, originalPath)
-			}
+	if len(steps) == 0 {
+		return nil
+	}
 
-			return fmt.Errorf("重命名文件夹失败 %s -> %s: %v", tempFolderName, finalFolderName, err)
-		}
+	before := m.readOrder(rootPath)
+	if err := m.renameBatch(rootPath, steps, i18n.ErrRenameFolder); err != nil {
+		return err
 	}
 
+	// 文件夹名已体现顺序，旧的顺序清单不再适用
+	m.removeOrder(rootPath)
+
+	entry := newHistoryEntry(HistoryBatchRename, rootPath, filepath.Base(rootPath))
+	entry.Steps = steps
+	entry.Before = before
+	entry.IsDir = true
+	m.recordHistory(entry)
 	return nil
 }
 
@@ -345,8 +428,63 @@ func (m *MemeFile) cleanFolderName(folderName string) string {
 	return folderName
 }
 
-func (m *MemeFile) DownloadTgStickerSet(stickerSetName string, savePath string, botToken string, proxyURL string, needProxy bool) error {
-	m.downloader = sticker.NewTelegramDownloader(m.ctx, botToken, proxyURL, needProxy)
+// newDownloader 创建应用当前设置的下载器，profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) newDownloader(botToken string, proxyURL string, needProxy bool, profileID string) (*sticker.TelegramDownloader, error) {
+	m.settingsMu.Lock()
+	if profileID == "" {
+		profileID = m.settings.ConversionProfile
+	}
+	profile, ok := m.findProfile(profileID)
+	keepSource := m.settings.KeepStickerSource
+	m.settingsMu.Unlock()
+
+	if !ok {
+		return nil, i18n.Errorf(i18n.ErrProfileNotFound, profileID)
+	}
+
+	downloader, err := sticker.NewTelegramDownloader(m.ctx, botToken, proxyURL, needProxy)
+	if err != nil {
+		return nil, err
+	}
+	downloader.SetKeepSource(keepSource)
+	downloader.SetProfile(profile)
+	return downloader, nil
+}
+
+// stickerSavePath 解析贴纸集合引用（链接、@name 或名称），返回规范化的集合名称和保存路径
+// folderCode 为空时保存到根目录下与贴纸集合同名的文件夹
+func stickerSavePath(rootPath string, folderCode string, stickerSetRef string) (string, string, error) {
+	ref, err := sticker.ParseStickerSetRef(stickerSetRef)
+	if err != nil {
+		return "", "", err
+	}
+	if folderCode == "" {
+		folderCode = ref.Name
+	}
+	savePath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return "", "", err
+	}
+	return ref.Name, savePath, nil
+}
+
+// DownloadTgStickerSet 下载整个贴纸集合到根目录下的 folderCode 文件夹，返回每个贴纸的处理结果
+// folderCode 为空时使用集合名称，botToken 为空时使用已保存的 Bot Token，profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) DownloadTgStickerSet(stickerSetName string, rootPath string, folderCode string, botToken string, proxyURL string, needProxy bool, profileID string) (*sticker.DownloadResult, error) {
+	stickerSetName, savePath, err := stickerSavePath(rootPath, folderCode, stickerSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	botToken, err = m.resolveBotToken(botToken)
+	if err != nil {
+		return nil, err
+	}
+
+	downloader, err := m.newDownloader(botToken, proxyURL, needProxy, profileID)
+	if err != nil {
+		return nil, err
+	}
 
 	progressCallback := func(progress sticker.DownloadProgress) {
 		if m.ctx != nil {
@@ -358,41 +496,190 @@ func (m *MemeFile) DownloadTgStickerSet(stickerSetName string, savePath string,
 		}
 	}
 
-	return m.downloader.DownloadTgStickerSet(stickerSetName, savePath, progressCallback)
+	return downloader.DownloadTgStickerSet(stickerSetName, savePath, progressCallback)
+}
+
+// ParseTgStickerRef 解析贴纸链接、tg:// 链接、@name 或集合名称，返回规范化的集合名称和类型
+func (m *MemeFile) ParseTgStickerRef(input string) (sticker.StickerSetRef, error) {
+	return sticker.ParseStickerSetRef(input)
 }
 
-// DeleteTgStickerSet 删除Telegram贴纸集合文件夹
+// GetTgStickerSetInfo 获取贴纸集合的标题、类型、数量以及前 limit 个贴纸的缩略图
+// 缩略图缓存在临时目录中，通过文件加载器访问
+func (m *MemeFile) GetTgStickerSetInfo(stickerSetName string, limit int, botToken string, proxyURL string, needProxy bool) (*sticker.StickerSetInfo, error) {
+	if stickerSetName == "" {
+		return nil, i18n.Errorf(i18n.ErrStickerSetRequired)
+	}
+
+	botToken, err := m.resolveBotToken(botToken)
+	if err != nil {
+		return nil, err
+	}
+
+	downloader, err := sticker.NewTelegramDownloader(m.ctx, botToken, proxyURL, needProxy)
+	if err != nil {
+		return nil, err
+	}
+	return downloader.GetStickerSetInfo(stickerSetName, limit)
+}
+
+// TestProxy 测试当前代理设置能否访问 Telegram API，已保存 Bot Token 时同时检查 Token 是否有效
+// proxyURL 支持 http/https/socks5/socks5h 及用户名密码，为空或为 auto 时使用环境变量和系统代理
+func (m *MemeFile) TestProxy(proxyURL string, needProxy bool) (sticker.ProxyTestResult, error) {
+	botToken, _, err := m.tokens.get()
+	if err != nil {
+		log.Printf("读取 Bot Token 失败: %v", err)
+	}
+
+	downloader, err := sticker.NewTelegramDownloader(m.ctx, botToken, proxyURL, needProxy)
+	if err != nil {
+		return sticker.ProxyTestResult{}, err
+	}
+	return downloader.TestProxy(), nil
+}
+
+// DownloadTgStickers 只下载贴纸集合中选择的贴纸，可保存到已有文件夹或新文件夹
+// folderCode 为空时使用集合名称，profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) DownloadTgStickers(stickerSetName string, fileUniqueIDs []string, rootPath string, folderCode string, botToken string, proxyURL string, needProxy bool, profileID string) (*sticker.DownloadResult, error) {
+	stickerSetName, savePath, err := stickerSavePath(rootPath, folderCode, stickerSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	botToken, err = m.resolveBotToken(botToken)
+	if err != nil {
+		return nil, err
+	}
+
+	downloader, err := m.newDownloader(botToken, proxyURL, needProxy, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	progressCallback := func(progress sticker.DownloadProgress) {
+		if m.ctx != nil {
+			progressData := map[string]interface{}{
+				"stickerSetName": stickerSetName,
+				"progress":       progress,
+			}
+			runtime.EventsEmit(m.ctx, "telegram-download-progress", progressData)
+		}
+	}
+
+	return downloader.DownloadStickers(stickerSetName, fileUniqueIDs, savePath, progressCallback)
+}
+
+// DownloadTgCustomEmoji 根据自定义表情ID下载 Telegram 自定义表情到根目录下的 folderCode 文件夹
+// profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) DownloadTgCustomEmoji(emojiIDs []string, rootPath string, folderCode string, botToken string, proxyURL string, needProxy bool, profileID string) (*sticker.DownloadResult, error) {
+	if folderCode == "" {
+		return nil, i18n.Errorf(i18n.ErrFolderRequired)
+	}
+	savePath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return nil, err
+	}
+
+	botToken, err = m.resolveBotToken(botToken)
+	if err != nil {
+		return nil, err
+	}
+
+	downloader, err := m.newDownloader(botToken, proxyURL, needProxy, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	progressCallback := func(progress sticker.DownloadProgress) {
+		if m.ctx != nil {
+			progressData := map[string]interface{}{
+				"savePath": savePath,
+				"progress": progress,
+			}
+			runtime.EventsEmit(m.ctx, "telegram-emoji-download-progress", progressData)
+		}
+	}
+
+	return downloader.DownloadCustomEmoji(emojiIDs, savePath, progressCallback)
+}
+
+// ReconvertStickerSet 使用保留的原始文件和指定的转换配置重新生成贴纸集合的 GIF/PNG
+// profileID 为空时使用当前选择的转换配置
+func (m *MemeFile) ReconvertStickerSet(rootPath string, folderCode string, profileID string) (*sticker.DownloadResult, error) {
+	if folderCode == "" {
+		return nil, i18n.Errorf(i18n.ErrFolderRequired)
+	}
+	folderPath, err := resolvePath(rootPath, folderCode)
+	if err != nil {
+		return nil, err
+	}
+
+	converter, err := m.newDownloader("", "", false, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	progressCallback := func(progress sticker.DownloadProgress) {
+		if m.ctx != nil {
+			progressData := map[string]interface{}{
+				"folderPath": folderPath,
+				"progress":   progress,
+			}
+			runtime.EventsEmit(m.ctx, "telegram-reconvert-progress", progressData)
+		}
+	}
+
+	return converter.ReconvertStickerSet(folderPath, progressCallback)
+}
+
+// DeleteTgStickerSet 删除Telegram贴纸集合文件夹（移动到回收站）
 func (m *MemeFile) DeleteTgStickerSet(rootPath string, stickerSetName string) error {
-	// 构建贴纸集合的文件夹路径
-	stickerSetPath := filepath.Join(rootPath, stickerSetName)
+	// 构建贴纸集合的文件夹路径，确保位于根目录内
+	stickerSetPath, err := resolvePath(rootPath, stickerSetName)
+	if err != nil {
+		return err
+	}
 
 	// 检查文件夹是否存在
 	if _, err := os.Stat(stickerSetPath); os.IsNotExist(err) {
-		return fmt.Errorf("贴纸集合文件夹不存在: %s", stickerSetName)
+		return i18n.Errorf(i18n.ErrStickerSetNotFound, stickerSetName)
 	}
 
-	// 删除整个文件夹
-	if err := os.RemoveAll(stickerSetPath); err != nil {
-		return fmt.Errorf("删除贴纸集合失败: %v", err)
+	// 将整个文件夹移动到回收站
+	entry := newHistoryEntry(HistoryDelete, rootPath, stickerSetName)
+	item, err := m.moveToTrash(stickerSetPath, entry.ID)
+	if err != nil {
+		return i18n.Wrap(err, i18n.ErrDeleteStickerSet)
 	}
 
+	entry.Name = item.Name
+	entry.TrashID = item.ID
+	m.recordHistory(entry)
 	return nil
 }
 
-// DeleteMemeFile 删除单个表情包文件
+// DeleteMemeFile 删除单个表情包文件（移动到回收站）
 func (m *MemeFile) DeleteMemeFile(rootPath string, folderCode string, fileName string) error {
-	// 构建文件的完整路径
-	filePath := filepath.Join(rootPath, folderCode, fileName)
+	// 构建文件的完整路径，确保位于根目录内
+	filePath, err := resolvePath(rootPath, folderCode, fileName)
+	if err != nil {
+		return err
+	}
 
 	// 检查文件是否存在
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return fmt.Errorf("文件不存在: %s", fileName)
+		return i18n.Errorf(i18n.ErrFileNotFound, fileName)
 	}
 
-	// 删除文件
-	if err := os.Remove(filePath); err != nil {
-		return fmt.Errorf("删除文件失败: %v", err)
+	// 将文件移动到回收站
+	entry := newHistoryEntry(HistoryDelete, filepath.Dir(filePath), historyLabel(filepath.Dir(filePath), fileName))
+	item, err := m.moveToTrash(filePath, entry.ID)
+	if err != nil {
+		return i18n.Wrap(err, i18n.ErrDeleteFile)
 	}
 
+	entry.Name = item.Name
+	entry.TrashID = item.ID
+	m.recordHistory(entry)
 	return nil
 }
@@ -0,0 +1,171 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"mymeme/memeFile/i18n"
+)
+
+// orderFileName 顺序清单文件名，根目录下保存分类顺序，分类文件夹下保存表情顺序
+const orderFileName = ".order.json"
+
+// 排序方式
+const (
+	OrderModeRename   = "rename"   // 按顺序重命名文件和文件夹（tab_01.png、01_name）
+	OrderModeManifest = "manifest" // 将顺序保存到清单文件，不修改文件名
+)
+
+// orderManifest 顺序清单，只记录名称，清单中不存在的条目按名称排在最后
+type orderManifest struct {
+	Items []string `json:"items"`
+}
+
+// readOrder 读取目录下的顺序清单，清单不存在或损坏时返回空列表
+func (m *MemeFile) readOrder(dirPath string) []string {
+	var manifest orderManifest
+	if err := m.fileUtils.ReadJSON(filepath.Join(dirPath, orderFileName), &manifest); err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取顺序清单失败 %s: %v", dirPath, err)
+		}
+		return nil
+	}
+	return manifest.Items
+}
+
+// writeOrder 将顺序写入目录下的顺序清单，忽略空名称和重复名称
+func (m *MemeFile) writeOrder(dirPath string, names []string) error {
+	seen := make(map[string]bool, len(names))
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		items = append(items, name)
+	}
+
+	return m.fileUtils.WriteJSON(filepath.Join(dirPath, orderFileName), orderManifest{Items: items})
+}
+
+// removeOrder 删除目录下的顺序清单
+func (m *MemeFile) removeOrder(dirPath string) {
+	if err := os.Remove(filepath.Join(dirPath, orderFileName)); err != nil && !os.IsNotExist(err) {
+		log.Printf("删除顺序清单失败 %s: %v", dirPath, err)
+	}
+}
+
+// renameInOrder 顺序清单中的条目被重命名后同步更新清单，清单不存在时不做任何操作
+func (m *MemeFile) renameInOrder(dirPath string, oldName string, newName string) {
+	items := m.readOrder(dirPath)
+	for i, name := range items {
+		if name == oldName {
+			items[i] = newName
+			if err := m.writeOrder(dirPath, items); err != nil {
+				log.Printf("更新顺序清单失败 %s: %v", dirPath, err)
+			}
+			return
+		}
+	}
+}
+
+// sortByOrder 按顺序清单排列名称，清单中没有的名称按名称顺序排在最后
+func sortByOrder(names []string, order []string) []string {
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i
+	}
+
+	sorted := append([]string(nil), names...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, iok := rank[sorted[i]]
+		rj, jok := rank[sorted[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return sorted[i] < sorted[j]
+		}
+	})
+	return sorted
+}
+
+// orderMode 获取当前排序方式
+func (m *MemeFile) orderMode() string {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	return m.settings.OrderMode
+}
+
+// SaveMemeOrder 将分类文件夹内表情的顺序保存到顺序清单，不修改文件名
+func (m *MemeFile) SaveMemeOrder(folderPath string, fileNames []string) error {
+	if folderPath == "" {
+		return i18n.Errorf(i18n.ErrFolderRequired)
+	}
+	if len(fileNames) == 0 {
+		return i18n.Errorf(i18n.ErrFileListRequired)
+	}
+	if err := validateNames(fileNames); err != nil {
+		return err
+	}
+	if !m.fileUtils.PathExists(folderPath) {
+		return i18n.Errorf(i18n.ErrFolderNotFound, folderPath)
+	}
+
+	return m.saveOrder(folderPath, fileNames)
+}
+
+// SaveCategoryOrder 将根目录下分类文件夹的顺序保存到顺序清单，不修改文件夹名
+func (m *MemeFile) SaveCategoryOrder(rootPath string, folderNames []string) error {
+	if rootPath == "" {
+		return i18n.Errorf(i18n.ErrRootRequired)
+	}
+	if len(folderNames) == 0 {
+		return i18n.Errorf(i18n.ErrFolderListRequired)
+	}
+	if err := validateNames(folderNames); err != nil {
+		return err
+	}
+	if !m.fileUtils.PathExists(rootPath) {
+		return i18n.Errorf(i18n.ErrRootNotFound, rootPath)
+	}
+
+	return m.saveOrder(rootPath, folderNames)
+}
+
+// saveOrder 写入顺序清单并记录历史，以便撤销
+func (m *MemeFile) saveOrder(dirPath string, names []string) error {
+	before := m.readOrder(dirPath)
+	if err := m.writeOrder(dirPath, names); err != nil {
+		return err
+	}
+
+	entry := newHistoryEntry(HistoryReorder, dirPath, filepath.Base(dirPath))
+	entry.Before = before
+	entry.After = m.readOrder(dirPath)
+	m.recordHistory(entry)
+	return nil
+}
+
+// SetOrderMode 设置排序方式：rename 按顺序重命名文件，manifest 只保存顺序清单
+func (m *MemeFile) SetOrderMode(mode string) error {
+	if mode != OrderModeRename && mode != OrderModeManifest {
+		return i18n.Errorf(i18n.ErrUnsupportedOrderMode, mode)
+	}
+
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	m.settings.OrderMode = mode
+	return m.saveSettings()
+}
+
+// GetOrderMode 获取当前排序方式
+func (m *MemeFile) GetOrderMode() string {
+	return m.orderMode()
+}
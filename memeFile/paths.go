@@ -0,0 +1,104 @@
+package memeFile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// validateName 校验文件名或文件夹名，只允许单级名称
+// 拒绝空名称、只由点组成的名称（如 . 和 ..）、以点或空格结尾的名称（Windows 会去掉结尾的点和空格，
+// 实际指向另一个文件）、路径分隔符（/ 和 \）、盘符以及空字符
+func validateName(name string) error {
+	switch {
+	case name == "", strings.TrimRight(name, ". ") != name:
+		return i18n.Errorf(i18n.ErrInvalidName, name)
+	case strings.ContainsAny(name, `/\`+"\x00"):
+		return i18n.Errorf(i18n.ErrInvalidName, name)
+	case filepath.VolumeName(name) != "":
+		return i18n.Errorf(i18n.ErrInvalidName, name)
+	}
+	return nil
+}
+
+// isWithin 判断 path 是否在 root 目录内（不包括 root 本身）
+func isWithin(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// evalExisting 解析路径中已存在部分的符号链接（Windows 上包括目录联接），不存在的部分原样拼接
+func evalExisting(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	realParent, err := evalExisting(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(realParent, filepath.Base(path)), nil
+}
+
+// resolvePath 将根目录和若干级名称拼接为完整路径，确保结果位于根目录内且不是根目录本身
+// 解析符号链接后再次检查，避免通过指向根目录外的链接访问其他位置
+// 所有接收前端传入名称的绑定方法都应通过该函数构建路径
+func resolvePath(root string, names ...string) (string, error) {
+	if root == "" {
+		return "", i18n.Errorf(i18n.ErrRootRequired)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", i18n.Errorf(i18n.ErrPathOutsideRoot, root)
+	}
+
+	for _, name := range names {
+		if err := validateName(name); err != nil {
+			return "", err
+		}
+	}
+
+	path := filepath.Join(append([]string{root}, names...)...)
+	if path == root {
+		return "", i18n.Errorf(i18n.ErrRootProtected, root)
+	}
+	if !isWithin(root, path) {
+		return "", i18n.Errorf(i18n.ErrPathOutsideRoot, path)
+	}
+
+	realRoot, err := evalExisting(root)
+	if err != nil {
+		return "", i18n.Errorf(i18n.ErrPathOutsideRoot, path)
+	}
+	realPath, err := evalExisting(path)
+	if err != nil || !isWithin(realRoot, realPath) {
+		return "", i18n.Errorf(i18n.ErrPathOutsideRoot, path)
+	}
+	return path, nil
+}
+
+// validateNames 校验名称列表，空名称会被调用方跳过，因此不视为错误
+func validateNames(names []string) error {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if err := validateName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -8,3 +8,14 @@ import "mymeme/memeFile/platform/macos"
 func NewClipboard() Clipboard {
 	return macos.NewClipboard()
 }
+
+// NewKeyring 创建MacOS钥匙串实例
+func NewKeyring() Keyring {
+	return macos.NewKeyring()
+}
+
+// SystemProxy 读取MacOS系统代理设置
+func SystemProxy() ProxySettings {
+	url, bypass := macos.SystemProxy()
+	return ProxySettings{URL: url, Bypass: bypass}
+}
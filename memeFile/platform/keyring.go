@@ -0,0 +1,13 @@
+package platform
+
+// Keyring 定义系统凭据存储接口
+type Keyring interface {
+	// SetSecret 保存凭据，已存在时覆盖
+	SetSecret(service, account, secret string) error
+
+	// GetSecret 读取凭据，不存在时返回空字符串
+	GetSecret(service, account string) (string, error)
+
+	// DeleteSecret 删除凭据，不存在时不报错
+	DeleteSecret(service, account string) error
+}
@@ -0,0 +1,118 @@
+//go:build darwin
+
+package macos
+
+/*
+#cgo CFLAGS: -Wno-deprecated-declarations
+#cgo LDFLAGS: -framework Security -framework CoreFoundation
+#include <Security/Security.h>
+#include <stdlib.h>
+#include <string.h>
+
+int keychainSet(const char* service, const char* account, const char* secret, int secretLen) {
+    SecKeychainItemRef item = NULL;
+    OSStatus status = SecKeychainFindGenericPassword(NULL,
+        (UInt32)strlen(service), service, (UInt32)strlen(account), account, NULL, NULL, &item);
+    if (status == errSecSuccess) {
+        status = SecKeychainItemModifyAttributesAndData(item, NULL, (UInt32)secretLen, secret);
+        CFRelease(item);
+        return status;
+    }
+    return SecKeychainAddGenericPassword(NULL,
+        (UInt32)strlen(service), service, (UInt32)strlen(account), account, (UInt32)secretLen, secret, NULL);
+}
+
+int keychainGet(const char* service, const char* account, char** out, int* outLen) {
+    UInt32 length = 0;
+    void* data = NULL;
+    OSStatus status = SecKeychainFindGenericPassword(NULL,
+        (UInt32)strlen(service), service, (UInt32)strlen(account), account, &length, &data, NULL);
+    if (status != errSecSuccess) {
+        return status;
+    }
+    *out = (char*)malloc(length);
+    memcpy(*out, data, length);
+    *outLen = (int)length;
+    SecKeychainItemFreeContent(NULL, data);
+    return 0;
+}
+
+int keychainDelete(const char* service, const char* account) {
+    SecKeychainItemRef item = NULL;
+    OSStatus status = SecKeychainFindGenericPassword(NULL,
+        (UInt32)strlen(service), service, (UInt32)strlen(account), account, NULL, NULL, &item);
+    if (status != errSecSuccess) {
+        return status;
+    }
+    status = SecKeychainItemDelete(item);
+    CFRelease(item);
+    return status;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// errSecItemNotFound 钥匙串中不存在该条目
+const errSecItemNotFound = -25300
+
+// Keyring MacOS钥匙串实现
+type Keyring struct{}
+
+// NewKeyring 创建MacOS钥匙串实例
+func NewKeyring() *Keyring {
+	return &Keyring{}
+}
+
+// SetSecret 将凭据保存到钥匙串
+func (k *Keyring) SetSecret(service, account, secret string) error {
+	cService := C.CString(service)
+	defer C.free(unsafe.Pointer(cService))
+	cAccount := C.CString(account)
+	defer C.free(unsafe.Pointer(cAccount))
+	cSecret := C.CString(secret)
+	defer C.free(unsafe.Pointer(cSecret))
+
+	if status := C.keychainSet(cService, cAccount, cSecret, C.int(len(secret))); status != 0 {
+		return fmt.Errorf("保存凭据失败: OSStatus %d", int(status))
+	}
+	return nil
+}
+
+// GetSecret 从钥匙串读取凭据
+func (k *Keyring) GetSecret(service, account string) (string, error) {
+	cService := C.CString(service)
+	defer C.free(unsafe.Pointer(cService))
+	cAccount := C.CString(account)
+	defer C.free(unsafe.Pointer(cAccount))
+
+	var out *C.char
+	var outLen C.int
+	status := C.keychainGet(cService, cAccount, &out, &outLen)
+	if status == errSecItemNotFound {
+		return "", nil
+	}
+	if status != 0 {
+		return "", fmt.Errorf("读取凭据失败: OSStatus %d", int(status))
+	}
+	defer C.free(unsafe.Pointer(out))
+
+	return C.GoStringN(out, outLen), nil
+}
+
+// DeleteSecret 从钥匙串删除凭据
+func (k *Keyring) DeleteSecret(service, account string) error {
+	cService := C.CString(service)
+	defer C.free(unsafe.Pointer(cService))
+	cAccount := C.CString(account)
+	defer C.free(unsafe.Pointer(cAccount))
+
+	status := C.keychainDelete(cService, cAccount)
+	if status != 0 && status != errSecItemNotFound {
+		return fmt.Errorf("删除凭据失败: OSStatus %d", int(status))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build darwin
+
+package macos
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// SystemProxy 通过 scutil 读取系统代理，返回代理地址和不使用代理的主机列表
+func SystemProxy() (string, string) {
+	output, err := exec.Command("scutil", "--proxy").Output()
+	if err != nil {
+		return "", ""
+	}
+	return parseScutilProxy(output)
+}
+
+// parseScutilProxy 解析 scutil --proxy 的输出，依次使用 HTTPS、HTTP、SOCKS 代理
+func parseScutilProxy(output []byte) (string, string) {
+	values := make(map[string]string)
+	var exceptions []string
+	inExceptions := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if inExceptions {
+			if line == "}" {
+				inExceptions = false
+				continue
+			}
+			if _, host, ok := strings.Cut(line, " : "); ok {
+				exceptions = append(exceptions, strings.TrimPrefix(strings.TrimSpace(host), "*"))
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, " : ")
+		if !ok {
+			continue
+		}
+		if key == "ExceptionsList" {
+			inExceptions = true
+			continue
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+
+	bypass := strings.Join(exceptions, ",")
+	for _, p := range []struct{ prefix, scheme string }{
+		{"HTTPS", "http"},
+		{"HTTP", "http"},
+		{"SOCKS", "socks5"},
+	} {
+		if values[p.prefix+"Enable"] != "1" || values[p.prefix+"Proxy"] == "" {
+			continue
+		}
+		addr := values[p.prefix+"Proxy"]
+		if port := values[p.prefix+"Port"]; port != "" {
+			addr += ":" + port
+		}
+		return p.scheme + "://" + addr, bypass
+	}
+	return "", ""
+}
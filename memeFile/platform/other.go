@@ -0,0 +1,13 @@
+//go:build !windows && !darwin
+
+package platform
+
+// NewKeyring 其他系统暂不支持系统凭据存储，返回 nil 时使用加密文件保存
+func NewKeyring() Keyring {
+	return nil
+}
+
+// SystemProxy 其他系统不读取系统代理设置，只使用环境变量中的代理
+func SystemProxy() ProxySettings {
+	return ProxySettings{}
+}
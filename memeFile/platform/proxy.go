@@ -0,0 +1,7 @@
+package platform
+
+// ProxySettings 系统代理设置
+type ProxySettings struct {
+	URL    string // 代理地址，为空表示未启用系统代理
+	Bypass string // 不使用代理的主机列表，逗号分隔，格式与 NO_PROXY 相同
+}
@@ -8,3 +8,14 @@ import "mymeme/memeFile/platform/windows"
 func NewClipboard() Clipboard {
 	return windows.NewClipboard()
 }
+
+// NewKeyring 创建Windows凭据管理器实例
+func NewKeyring() Keyring {
+	return windows.NewKeyring()
+}
+
+// SystemProxy 读取Windows系统代理设置
+func SystemProxy() ProxySettings {
+	url, bypass := windows.SystemProxy()
+	return ProxySettings{URL: url, Bypass: bypass}
+}
@@ -0,0 +1,138 @@
+//go:build windows
+
+package windows
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// 凭据管理器相关API
+var (
+	Advapi32 = syscall.NewLazyDLL("advapi32.dll")
+
+	ProcCredWrite  = Advapi32.NewProc("CredWriteW")
+	ProcCredRead   = Advapi32.NewProc("CredReadW")
+	ProcCredDelete = Advapi32.NewProc("CredDeleteW")
+	ProcCredFree   = Advapi32.NewProc("CredFree")
+)
+
+// Windows 凭据常量
+const (
+	CRED_TYPE_GENERIC          = 1
+	CRED_PERSIST_LOCAL_MACHINE = 2
+	ERROR_NOT_FOUND            = 1168
+)
+
+// CREDENTIAL 结构体定义 - Windows凭据结构
+type CREDENTIAL struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// Keyring Windows凭据管理器实现
+type Keyring struct{}
+
+// NewKeyring 创建Windows凭据管理器实例
+func NewKeyring() *Keyring {
+	return &Keyring{}
+}
+
+// targetName 凭据名称，格式为 service:account
+func targetName(service, account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + account)
+}
+
+// SetSecret 将凭据保存到Windows凭据管理器
+func (k *Keyring) SetSecret(service, account, secret string) error {
+	if err := Advapi32.Load(); err != nil {
+		return fmt.Errorf("凭据管理器不可用: %v", err)
+	}
+
+	target, err := targetName(service, account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+
+	blob := []byte(secret)
+	cred := CREDENTIAL{
+		Type:               CRED_TYPE_GENERIC,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            CRED_PERSIST_LOCAL_MACHINE,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	ret, _, callErr := ProcCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if ret == 0 {
+		return fmt.Errorf("保存凭据失败: %v", callErr)
+	}
+	return nil
+}
+
+// GetSecret 从Windows凭据管理器读取凭据
+func (k *Keyring) GetSecret(service, account string) (string, error) {
+	if err := Advapi32.Load(); err != nil {
+		return "", fmt.Errorf("凭据管理器不可用: %v", err)
+	}
+
+	target, err := targetName(service, account)
+	if err != nil {
+		return "", err
+	}
+
+	var cred *CREDENTIAL
+	ret, _, callErr := ProcCredRead.Call(uintptr(unsafe.Pointer(target)), CRED_TYPE_GENERIC, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errno, ok := callErr.(syscall.Errno); ok && errno == ERROR_NOT_FOUND {
+			return "", nil
+		}
+		return "", fmt.Errorf("读取凭据失败: %v", callErr)
+	}
+	defer ProcCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 || cred.CredentialBlob == nil {
+		return "", nil
+	}
+	blob := unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)
+	return string(blob), nil
+}
+
+// DeleteSecret 从Windows凭据管理器删除凭据
+func (k *Keyring) DeleteSecret(service, account string) error {
+	if err := Advapi32.Load(); err != nil {
+		return fmt.Errorf("凭据管理器不可用: %v", err)
+	}
+
+	target, err := targetName(service, account)
+	if err != nil {
+		return err
+	}
+
+	ret, _, callErr := ProcCredDelete.Call(uintptr(unsafe.Pointer(target)), CRED_TYPE_GENERIC, 0)
+	if ret == 0 {
+		if errno, ok := callErr.(syscall.Errno); ok && errno == ERROR_NOT_FOUND {
+			return nil
+		}
+		return fmt.Errorf("删除凭据失败: %v", callErr)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build windows
+
+package windows
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// internetSettingsKey 系统代理设置所在的注册表项
+const internetSettingsKey = `Software\Microsoft\Windows\CurrentVersion\Internet Settings`
+
+// SystemProxy 从注册表读取系统代理，返回代理地址和不使用代理的主机列表
+func SystemProxy() (string, string) {
+	keyName, err := syscall.UTF16PtrFromString(internetSettingsKey)
+	if err != nil {
+		return "", ""
+	}
+
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, keyName, 0, syscall.KEY_READ, &key); err != nil {
+		return "", ""
+	}
+	defer syscall.RegCloseKey(key)
+
+	if enabled, ok := readDWORD(key, "ProxyEnable"); !ok || enabled == 0 {
+		return "", ""
+	}
+
+	server := parseProxyServer(readString(key, "ProxyServer"))
+	if server == "" {
+		return "", ""
+	}
+	return server, parseProxyOverride(readString(key, "ProxyOverride"))
+}
+
+// parseProxyServer 解析 ProxyServer，格式为 host:port 或 http=host:port;https=host:port;socks=host:port
+func parseProxyServer(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || !strings.Contains(value, "=") {
+		return value
+	}
+
+	servers := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		if scheme, addr, ok := strings.Cut(strings.TrimSpace(part), "="); ok && addr != "" {
+			servers[strings.ToLower(scheme)] = addr
+		}
+	}
+
+	if addr := servers["https"]; addr != "" {
+		return addr
+	}
+	if addr := servers["http"]; addr != "" {
+		return addr
+	}
+	if addr := servers["socks"]; addr != "" {
+		return "socks5://" + addr
+	}
+	return ""
+}
+
+// parseProxyOverride 将分号分隔的 ProxyOverride 转换为逗号分隔的 NO_PROXY 格式，<local> 表示不含点的本地主机名
+func parseProxyOverride(value string) string {
+	var hosts []string
+	for _, host := range strings.Split(value, ";") {
+		host = strings.TrimSpace(host)
+		switch {
+		case host == "":
+		case strings.EqualFold(host, "<local>"):
+			hosts = append(hosts, "localhost", "127.0.0.1", "::1")
+		default:
+			hosts = append(hosts, strings.TrimPrefix(host, "*"))
+		}
+	}
+	return strings.Join(hosts, ",")
+}
+
+// readDWORD 读取 DWORD 类型的注册表值
+func readDWORD(key syscall.Handle, name string) (uint32, bool) {
+	valueName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, false
+	}
+
+	var value, valueType uint32
+	size := uint32(unsafe.Sizeof(value))
+	if err := syscall.RegQueryValueEx(key, valueName, nil, &valueType, (*byte)(unsafe.Pointer(&value)), &size); err != nil {
+		return 0, false
+	}
+	return value, valueType == syscall.REG_DWORD
+}
+
+// readString 读取字符串类型的注册表值
+func readString(key syscall.Handle, name string) string {
+	valueName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return ""
+	}
+
+	var valueType, size uint32
+	if err := syscall.RegQueryValueEx(key, valueName, nil, &valueType, nil, &size); err != nil || size == 0 {
+		return ""
+	}
+	if valueType != syscall.REG_SZ && valueType != syscall.REG_EXPAND_SZ {
+		return ""
+	}
+
+	buf := make([]uint16, (size+1)/2)
+	if err := syscall.RegQueryValueEx(key, valueName, nil, &valueType, (*byte)(unsafe.Pointer(&buf[0])), &size); err != nil {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
@@ -0,0 +1,213 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/sticker"
+)
+
+// renameJournalFileName 批量重命名日志文件名，保存在应用配置目录下
+const renameJournalFileName = "rename_journal.json"
+
+// 批量重命名阶段
+const (
+	renamePhaseStaging    = "staging"    // 正在将原名称重命名为临时名称，中断后回滚
+	renamePhaseCommitting = "committing" // 所有条目都已是临时名称，正在重命名为最终名称，中断后继续完成
+)
+
+// renameStep 批量重命名中的一项，名称都相对于 renameJournal.Dir
+type renameStep struct {
+	Old   string `json:"old"`   // 原名称
+	Temp  string `json:"temp"`  // 临时名称
+	Final string `json:"final"` // 最终名称
+}
+
+// renameJournal 批量重命名日志，在移动任何文件之前写入，完成或回滚后删除
+type renameJournal struct {
+	Dir   string       `json:"dir"`
+	Phase string       `json:"phase"`
+	Steps []renameStep `json:"steps"`
+}
+
+// renameJournalPath 获取重命名日志文件路径
+func (m *MemeFile) renameJournalPath() (string, error) {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, renameJournalFileName), nil
+}
+
+// writeRenameJournal 写入重命名日志
+func (m *MemeFile) writeRenameJournal(journal *renameJournal) error {
+	path, err := m.renameJournalPath()
+	if err != nil {
+		return err
+	}
+	if err := m.fileUtils.WriteJSON(path, journal); err != nil {
+		return i18n.Wrap(err, i18n.ErrRenameJournal)
+	}
+	return nil
+}
+
+// removeRenameJournal 删除重命名日志
+func (m *MemeFile) removeRenameJournal() {
+	path, err := m.renameJournalPath()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("删除重命名日志失败: %v", err)
+	}
+}
+
+// renameBatch 通过临时名称批量重命名，避免名称冲突
+// 移动文件前先写入日志；任何一步失败时回滚所有已完成的步骤，回滚未完成时保留日志，下次启动时恢复
+func (m *MemeFile) renameBatch(dir string, steps []renameStep, code i18n.Code) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	m.renameMu.Lock()
+	defer m.renameMu.Unlock()
+
+	// 最终名称不能覆盖本批次以外的文件，临时名称不能已存在
+	olds := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		olds[step.Old] = true
+	}
+	for _, step := range steps {
+		if !olds[step.Final] && m.fileUtils.PathExists(filepath.Join(dir, step.Final)) {
+			return i18n.Errorf(i18n.ErrFileExists, step.Final)
+		}
+		if m.fileUtils.PathExists(filepath.Join(dir, step.Temp)) {
+			return i18n.Errorf(i18n.ErrFileExists, step.Temp)
+		}
+	}
+
+	journal := &renameJournal{Dir: dir, Phase: renamePhaseStaging, Steps: steps}
+	if err := m.writeRenameJournal(journal); err != nil {
+		return err
+	}
+
+	// 将所有条目重命名为临时名称
+	for i, step := range steps {
+		if err := os.Rename(filepath.Join(dir, step.Old), filepath.Join(dir, step.Temp)); err != nil {
+			renameErr := i18n.Wrap(err, code, step.Old, step.Temp)
+			return m.rollbackRenames(journal, steps[:i], nil, renameErr)
+		}
+	}
+
+	journal.Phase = renamePhaseCommitting
+	if err := m.writeRenameJournal(journal); err != nil {
+		return m.rollbackRenames(journal, steps, nil, err)
+	}
+
+	// 将临时名称重命名为最终名称
+	for i, step := range steps {
+		if err := os.Rename(filepath.Join(dir, step.Temp), filepath.Join(dir, step.Final)); err != nil {
+			renameErr := i18n.Wrap(err, code, step.Temp, step.Final)
+			return m.rollbackRenames(journal, steps, steps[:i], renameErr)
+		}
+	}
+
+	m.removeRenameJournal()
+
+	// 自定义图标或贴纸被重命名时同步更新分类信息和贴纸清单
+	renames := make(map[string]string, len(steps))
+	for _, step := range steps {
+		renames[step.Old] = step.Final
+	}
+	m.renameMetaIcon(dir, renames)
+	if err := sticker.RenameManifestOutputs(dir, renames); err != nil {
+		log.Printf("更新贴纸清单失败 %s: %v", dir, err)
+	}
+	return nil
+}
+
+// rollbackRenames 回滚批量重命名：先将已完成的最终名称改回临时名称，再将临时名称改回原名称
+// staged 为已改为临时名称的步骤，committed 为已改为最终名称的步骤
+func (m *MemeFile) rollbackRenames(journal *renameJournal, staged []renameStep, committed []renameStep, cause error) error {
+	dir := journal.Dir
+	failed := false
+
+	for i := len(committed) - 1; i >= 0; i-- {
+		step := committed[i]
+		if err := os.Rename(filepath.Join(dir, step.Final), filepath.Join(dir, step.Temp)); err != nil {
+			log.Printf("回滚重命名失败 %s -> %s: %v", step.Final, step.Temp, err)
+			failed = true
+		}
+	}
+
+	// 最终名称已全部撤销，日志回到暂存阶段，中断后由启动恢复回滚
+	if journal.Phase != renamePhaseStaging {
+		journal.Phase = renamePhaseStaging
+		if err := m.writeRenameJournal(journal); err != nil {
+			log.Printf("更新重命名日志失败: %v", err)
+		}
+	}
+
+	for i := len(staged) - 1; i >= 0; i-- {
+		step := staged[i]
+		if err := os.Rename(filepath.Join(dir, step.Temp), filepath.Join(dir, step.Old)); err != nil {
+			log.Printf("回滚重命名失败 %s -> %s: %v", step.Temp, step.Old, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		return i18n.Wrap(cause, i18n.ErrRenameRollback)
+	}
+	m.removeRenameJournal()
+	return cause
+}
+
+// recoverRenames 启动时检查上次未完成的批量重命名
+// 暂存阶段中断时回滚到原名称，提交阶段中断时继续完成到最终名称
+func (m *MemeFile) recoverRenames() {
+	path, err := m.renameJournalPath()
+	if err != nil {
+		return
+	}
+
+	var journal renameJournal
+	if err := m.fileUtils.ReadJSON(path, &journal); err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("读取重命名日志失败: %v", err)
+		}
+		return
+	}
+
+	failed := false
+	for _, step := range journal.Steps {
+		tempPath := filepath.Join(journal.Dir, step.Temp)
+		if !m.fileUtils.PathExists(tempPath) {
+			continue
+		}
+
+		target := step.Old
+		if journal.Phase == renamePhaseCommitting {
+			target = step.Final
+		}
+		targetPath := filepath.Join(journal.Dir, target)
+		if m.fileUtils.PathExists(targetPath) {
+			log.Printf("恢复重命名失败，目标已存在: %s", targetPath)
+			failed = true
+			continue
+		}
+		if err := os.Rename(tempPath, targetPath); err != nil {
+			log.Printf("恢复重命名失败 %s -> %s: %v", step.Temp, target, err)
+			failed = true
+		}
+	}
+
+	if failed {
+		log.Printf("上次批量重命名未能完全恢复，保留日志: %s", path)
+		return
+	}
+	log.Printf("已恢复上次中断的批量重命名: %s (%s)", journal.Dir, journal.Phase)
+	m.removeRenameJournal()
+}
@@ -0,0 +1,45 @@
+package memeFile
+
+import (
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/utils"
+)
+
+// SanitizeReport 结构体 - 文件名检查和修正的结果
+type SanitizeReport struct {
+	DryRun  bool              `json:"dryRun"`  // 是否只检查不重命名
+	Issues  []utils.NameIssue `json:"issues"`  // 需要修正的名称
+	Renamed int               `json:"renamed"` // 已重命名数量
+	Failed  int               `json:"failed"`  // 重命名失败数量
+}
+
+// SanitizeNames 检查根目录下分类文件夹和表情的文件名，修正包含特殊字符等问题的名称
+// dryRun 为 true 时只返回检查结果，不做任何修改
+func (m *MemeFile) SanitizeNames(rootPath string, dryRun bool) (*SanitizeReport, error) {
+	if rootPath == "" {
+		return nil, i18n.Errorf(i18n.ErrRootRequired)
+	}
+	if !m.fileUtils.IsDir(rootPath) {
+		return nil, i18n.Errorf(i18n.ErrRootNotFound, rootPath)
+	}
+
+	report := &SanitizeReport{DryRun: dryRun, Issues: m.fileUtils.ScanNames(rootPath)}
+	if report.Issues == nil {
+		report.Issues = []utils.NameIssue{}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	// 先重命名文件再重命名文件夹，同时更新顺序清单和分类图标
+	for i := range report.Issues {
+		issue := &report.Issues[i]
+		if err := m.renameFile(issue.Dir, issue.Name, issue.NewName); err != nil {
+			issue.Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Renamed++
+	}
+	return report, nil
+}
@@ -0,0 +1,218 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/sticker"
+)
+
+// settingsFileName 后端设置文件名，保存在应用配置目录下
+const settingsFileName = "settings.json"
+
+// Settings 结构体 - 需要持久化的后端设置
+type Settings struct {
+	KeepStickerSource bool                        `json:"keepStickerSource"` // 下载贴纸时是否保留原始文件
+	ConversionProfile string                      `json:"conversionProfile"` // 当前使用的转换配置ID
+	CustomProfiles    []sticker.ConversionProfile `json:"customProfiles"`    // 用户自定义的转换配置
+
+	DownloadConcurrency int `json:"downloadConcurrency"` // 下载队列同时下载的集合数量
+
+	Language string `json:"language"` // 错误信息使用的语言：zh-CN / en
+
+	OrderMode string `json:"orderMode"` // 排序方式：rename / manifest
+
+	TrashRetentionDays int `json:"trashRetentionDays"` // 回收站保留天数，0 表示不自动清理
+}
+
+// defaultSettings 默认设置
+func defaultSettings() Settings {
+	return Settings{
+		ConversionProfile:   sticker.DefaultProfile.ID,
+		DownloadConcurrency: defaultDownloadConcurrency,
+		Language:            i18n.DefaultLanguage,
+		OrderMode:           OrderModeRename,
+		TrashRetentionDays:  defaultTrashRetentionDays,
+	}
+}
+
+// settingsPath 获取设置文件路径
+func (m *MemeFile) settingsPath() (string, error) {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, settingsFileName), nil
+}
+
+// loadSettings 从设置文件读取设置，文件不存在或损坏时使用默认设置
+func (m *MemeFile) loadSettings() {
+	m.settings = defaultSettings()
+
+	path, err := m.settingsPath()
+	if err != nil {
+		log.Printf("读取设置失败: %v", err)
+		return
+	}
+
+	if err := m.fileUtils.ReadJSON(path, &m.settings); err != nil && !os.IsNotExist(err) {
+		log.Printf("解析设置文件失败 %s: %v", path, err)
+		m.settings = defaultSettings()
+	}
+
+	if err := i18n.SetLanguage(m.settings.Language); err != nil {
+		m.settings.Language = i18n.DefaultLanguage
+		i18n.SetLanguage(m.settings.Language)
+	}
+}
+
+// saveSettings 将当前设置写入设置文件，调用方需持有 settingsMu
+func (m *MemeFile) saveSettings() error {
+	path, err := m.settingsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := m.fileUtils.WriteJSON(path, m.settings); err != nil {
+		return i18n.Wrap(err, i18n.ErrSaveSettings)
+	}
+	return nil
+}
+
+// SetKeepStickerSource 设置下载贴纸时是否在 .source 子目录中保留原始文件
+func (m *MemeFile) SetKeepStickerSource(keep bool) error {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	m.settings.KeepStickerSource = keep
+	return m.saveSettings()
+}
+
+// GetKeepStickerSource 获取是否保留原始贴纸文件
+func (m *MemeFile) GetKeepStickerSource() bool {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	return m.settings.KeepStickerSource
+}
+
+// GetConversionProfiles 获取所有可用的转换配置（预设 + 自定义）
+func (m *MemeFile) GetConversionProfiles() []sticker.ConversionProfile {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	profiles := sticker.PresetProfiles()
+	return append(profiles, m.settings.CustomProfiles...)
+}
+
+// GetConversionProfile 获取当前选择的转换配置
+func (m *MemeFile) GetConversionProfile() sticker.ConversionProfile {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	profile, _ := m.findProfile(m.settings.ConversionProfile)
+	return profile
+}
+
+// SetConversionProfile 选择后续下载使用的转换配置
+func (m *MemeFile) SetConversionProfile(profileID string) error {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	if _, ok := m.findProfile(profileID); !ok {
+		return i18n.Errorf(i18n.ErrProfileNotFound, profileID)
+	}
+
+	m.settings.ConversionProfile = profileID
+	return m.saveSettings()
+}
+
+// SaveConversionProfile 新增或更新自定义转换配置
+func (m *MemeFile) SaveConversionProfile(profile sticker.ConversionProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	if sticker.IsPresetProfile(profile.ID) {
+		return i18n.Errorf(i18n.ErrPresetProfileReadonly, profile.ID)
+	}
+
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	for i, p := range m.settings.CustomProfiles {
+		if p.ID == profile.ID {
+			m.settings.CustomProfiles[i] = profile
+			return m.saveSettings()
+		}
+	}
+
+	m.settings.CustomProfiles = append(m.settings.CustomProfiles, profile)
+	return m.saveSettings()
+}
+
+// DeleteConversionProfile 删除自定义转换配置，若正在使用则切换回默认配置
+func (m *MemeFile) DeleteConversionProfile(profileID string) error {
+	if sticker.IsPresetProfile(profileID) {
+		return i18n.Errorf(i18n.ErrPresetProfileUndeleted, profileID)
+	}
+
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	for i, p := range m.settings.CustomProfiles {
+		if p.ID == profileID {
+			m.settings.CustomProfiles = append(m.settings.CustomProfiles[:i], m.settings.CustomProfiles[i+1:]...)
+			if m.settings.ConversionProfile == profileID {
+				m.settings.ConversionProfile = sticker.DefaultProfile.ID
+			}
+			return m.saveSettings()
+		}
+	}
+
+	return i18n.Errorf(i18n.ErrProfileNotFound, profileID)
+}
+
+// findProfile 按ID查找转换配置，找不到时返回默认配置，调用方需持有 settingsMu
+func (m *MemeFile) findProfile(profileID string) (sticker.ConversionProfile, bool) {
+	for _, p := range sticker.PresetProfiles() {
+		if p.ID == profileID {
+			return p, true
+		}
+	}
+	for _, p := range m.settings.CustomProfiles {
+		if p.ID == profileID {
+			return p, true
+		}
+	}
+	return sticker.DefaultProfile, false
+}
+
+// SetLanguage 设置后端错误信息使用的语言（zh-CN / en）
+func (m *MemeFile) SetLanguage(lang string) error {
+	if err := i18n.SetLanguage(lang); err != nil {
+		return err
+	}
+
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	m.settings.Language = i18n.Language()
+	return m.saveSettings()
+}
+
+// GetLanguage 获取后端错误信息使用的语言
+func (m *MemeFile) GetLanguage() string {
+	return i18n.Language()
+}
+
+// GetLanguages 获取支持的语言列表
+func (m *MemeFile) GetLanguages() []string {
+	return i18n.Languages()
+}
+
+// GetMessageCatalogue 获取指定语言的消息目录（错误码 -> 消息格式），lang 为空时使用当前语言
+func (m *MemeFile) GetMessageCatalogue(lang string) map[string]string {
+	return i18n.Catalogue(lang)
+}
@@ -0,0 +1,252 @@
+package sticker
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"io"
+	"os"
+
+	"mymeme/memeFile/i18n"
+)
+
+// pngSignature PNG 文件头
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// APNG 帧控制参数
+const (
+	apngDisposeBackground = 1 // 下一帧绘制前将当前帧区域清空为透明
+	apngBlendSource       = 0 // 直接覆盖，不与上一帧混合
+)
+
+// apngWriter 写入 APNG 数据块
+type apngWriter struct {
+	w   io.Writer
+	seq uint32 // fcTL/fdAT 共用的序列号
+	err error
+}
+
+// writeChunk 写入一个 PNG 数据块（长度 + 类型 + 数据 + CRC）
+func (aw *apngWriter) writeChunk(chunkType string, data []byte) {
+	if aw.err != nil {
+		return
+	}
+
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], chunkType)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, b := range [][]byte{header, data, footer} {
+		if _, err := aw.w.Write(b); err != nil {
+			aw.err = err
+			return
+		}
+	}
+}
+
+// nextSeq 返回下一个序列号
+func (aw *apngWriter) nextSeq() uint32 {
+	seq := aw.seq
+	aw.seq++
+	return seq
+}
+
+// encodeAPNG 将 RGBA 帧编码为带完整 alpha 通道的 APNG
+func (td *TelegramDownloader) encodeAPNG(frames []*image.RGBA, plan framePlan, outputPath string) error {
+	if len(frames) == 0 {
+		return i18n.Errorf(i18n.ErrNoFrames)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return i18n.Wrap(err, i18n.ErrCreateOutput, outputPath)
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	if _, err := bw.Write(pngSignature); err != nil {
+		return i18n.Wrap(err, i18n.ErrEncodeAPNG)
+	}
+
+	bounds := frames[0].Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	aw := &apngWriter{w: bw}
+
+	// IHDR: 8 位 RGBA，非隔行
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(height))
+	ihdr[8] = 8 // 位深
+	ihdr[9] = 6 // 颜色类型: RGBA
+	aw.writeChunk("IHDR", ihdr)
+
+	// acTL: 帧数 + 播放次数（0 表示无限循环）
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], uint32(len(frames)))
+	binary.BigEndian.PutUint32(actl[4:8], uint32(max(td.profile.Loop, 0)))
+	aw.writeChunk("acTL", actl)
+
+	for i, frame := range frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:4], aw.nextSeq())
+		binary.BigEndian.PutUint32(fctl[4:8], uint32(width))
+		binary.BigEndian.PutUint32(fctl[8:12], uint32(height))
+		binary.BigEndian.PutUint16(fctl[20:22], uint16(plan.delays[i])) // 延迟分子
+		binary.BigEndian.PutUint16(fctl[22:24], 100)                    // 延迟分母: 1/100 秒
+		fctl[24] = apngDisposeBackground
+		fctl[25] = apngBlendSource
+		aw.writeChunk("fcTL", fctl)
+
+		data, err := compressFrame(frame)
+		if err != nil {
+			return i18n.Wrap(err, i18n.ErrEncodeAPNG)
+		}
+
+		if i == 0 {
+			// 第一帧使用 IDAT，不支持 APNG 的查看器会显示为静态图
+			aw.writeChunk("IDAT", data)
+		} else {
+			fdat := make([]byte, 4, 4+len(data))
+			binary.BigEndian.PutUint32(fdat, aw.nextSeq())
+			aw.writeChunk("fdAT", append(fdat, data...))
+		}
+	}
+
+	aw.writeChunk("IEND", nil)
+	if aw.err != nil {
+		return i18n.Wrap(aw.err, i18n.ErrEncodeAPNG)
+	}
+
+	return bw.Flush()
+}
+
+// compressFrame 将预乘 alpha 的 RGBA 帧转换为非预乘的 PNG 扫描行，逐行选择滤波器后压缩
+func compressFrame(frame *image.RGBA) ([]byte, error) {
+	bounds := frame.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	rowLen := width * 4
+
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	prev := make([]byte, rowLen)
+	cur := make([]byte, rowLen)
+	filtered := make([]byte, rowLen+1)
+	best := make([]byte, rowLen+1)
+
+	for y := 0; y < height; y++ {
+		row := frame.Pix[y*frame.Stride : y*frame.Stride+rowLen]
+		unpremultiply(cur, row)
+
+		// 依次尝试 None/Sub/Up/Paeth 滤波，选择绝对值之和最小的结果
+		bestSum := -1
+		for ft := byte(0); ft <= 4; ft++ {
+			if ft == 3 {
+				continue // 跳过 Average 滤波，效果与 Paeth 接近
+			}
+			filtered[0] = ft
+			sum := filterRow(filtered[1:], cur, prev, ft)
+			if bestSum < 0 || sum < bestSum {
+				bestSum = sum
+				copy(best, filtered)
+			}
+		}
+
+		if _, err := zw.Write(best); err != nil {
+			return nil, err
+		}
+		prev, cur = cur, prev
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unpremultiply 将预乘 alpha 的 RGBA 像素转换为非预乘颜色
+func unpremultiply(dst, src []byte) {
+	for i := 0; i+3 < len(src); i += 4 {
+		a := src[i+3]
+		switch a {
+		case 0:
+			dst[i], dst[i+1], dst[i+2], dst[i+3] = 0, 0, 0, 0
+		case 255:
+			copy(dst[i:i+4], src[i:i+4])
+		default:
+			dst[i] = uint8(min(int(src[i])*255/int(a), 255))
+			dst[i+1] = uint8(min(int(src[i+1])*255/int(a), 255))
+			dst[i+2] = uint8(min(int(src[i+2])*255/int(a), 255))
+			dst[i+3] = a
+		}
+	}
+}
+
+// filterRow 对一行像素应用 PNG 滤波，返回滤波结果的绝对值之和
+func filterRow(dst, cur, prev []byte, filterType byte) int {
+	const bpp = 4
+	sum := 0
+	for i := range cur {
+		var left, up, upLeft byte
+		if i >= bpp {
+			left = cur[i-bpp]
+			upLeft = prev[i-bpp]
+		}
+		up = prev[i]
+
+		var v byte
+		switch filterType {
+		case 0:
+			v = cur[i]
+		case 1:
+			v = cur[i] - left
+		case 2:
+			v = cur[i] - up
+		case 4:
+			v = cur[i] - paeth(left, up, upLeft)
+		}
+		dst[i] = v
+
+		if v < 128 {
+			sum += int(v)
+		} else {
+			sum += 256 - int(v)
+		}
+	}
+	return sum
+}
+
+// paeth PNG Paeth 预测器
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa := abs(p - int(a))
+	pb := abs(p - int(b))
+	pc := abs(p - int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	} else if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,128 @@
+package sticker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"mymeme/memeFile/i18n"
+)
+
+// maxCustomEmojiIDs getCustomEmojiStickers 单次请求最多支持的表情ID数量
+const maxCustomEmojiIDs = 200
+
+// withProfile 返回使用指定转换配置的下载器副本，共享同一个 HTTP 客户端
+func (td *TelegramDownloader) withProfile(profile ConversionProfile) *TelegramDownloader {
+	clone := *td
+	clone.profile = profile.normalize()
+	return &clone
+}
+
+// GetCustomEmojiStickers 根据自定义表情ID获取贴纸信息
+func (td *TelegramDownloader) GetCustomEmojiStickers(emojiIDs []string) ([]TelegramSticker, error) {
+	var stickers []TelegramSticker
+
+	for start := 0; start < len(emojiIDs); start += maxCustomEmojiIDs {
+		end := min(start+maxCustomEmojiIDs, len(emojiIDs))
+
+		ids, err := json.Marshal(emojiIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		query := url.Values{"custom_emoji_ids": {string(ids)}}
+		apiURL := fmt.Sprintf("%s/bot%s/getCustomEmojiStickers?%s", telegramAPIBase, td.botToken, query.Encode())
+		resp, err := td.get(apiURL)
+		if err != nil {
+			return nil, i18n.Wrap(err, i18n.ErrFetchCustomEmoji)
+		}
+
+		var apiResp TelegramCustomEmojiResponse
+		err = json.NewDecoder(resp.Body).Decode(&apiResp)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && apiResp.Description == "" {
+			return nil, i18n.Errorf(i18n.ErrHTTPStatus, resp.StatusCode, resp.Status)
+		}
+		if err != nil {
+			return nil, i18n.Wrap(err, i18n.ErrParseResponse)
+		}
+		if !apiResp.OK {
+			return nil, i18n.Errorf(i18n.ErrAPI, td.redact(apiResp.Description))
+		}
+
+		for _, sticker := range apiResp.Result {
+			// 部分客户端返回的贴纸缺少 type 字段，这里统一标记为自定义表情
+			sticker.Type = StickerTypeCustomEmoji
+			stickers = append(stickers, sticker)
+		}
+	}
+
+	return stickers, nil
+}
+
+// customEmojiSet 构建自定义表情所属集合的清单信息（不含标题），表情来自不同集合时集合名称为空
+func customEmojiSet(stickers []TelegramSticker) *TelegramStickerSet {
+	set := &TelegramStickerSet{StickerType: StickerTypeCustomEmoji}
+	for i, sticker := range stickers {
+		if i > 0 && sticker.SetName != set.Name {
+			set.Name = ""
+			break
+		}
+		set.Name = sticker.SetName
+	}
+	return set
+}
+
+// DownloadCustomEmoji 下载指定ID的自定义表情到 savePath，返回每个表情的处理结果
+func (td *TelegramDownloader) DownloadCustomEmoji(emojiIDs []string, savePath string, progressCallback func(DownloadProgress)) (*DownloadResult, error) {
+	if len(emojiIDs) == 0 {
+		return nil, i18n.Errorf(i18n.ErrEmojiIDsRequired)
+	}
+
+	result := newDownloadResult(savePath)
+	tracker := newProgressTracker(result, progressCallback)
+
+	tracker.phase(PhaseFetching)
+	stickers, err := td.GetCustomEmojiStickers(emojiIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(stickers) == 0 {
+		return nil, i18n.Errorf(i18n.ErrCustomEmojiNotFound)
+	}
+
+	// 接口没有返回的表情ID记为跳过
+	found := make(map[string]bool, len(stickers))
+	for _, sticker := range stickers {
+		found[sticker.CustomEmojiID] = true
+	}
+	for _, id := range emojiIDs {
+		if !found[id] {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	result.Total = len(stickers)
+
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrCreateDir)
+	}
+
+	tracker.phase(PhaseDownloading)
+	td.downloadStickers(stickers, savePath, tracker)
+
+	log.Printf("自定义表情下载完成: 成功 %d 个，失败 %d 个", len(result.Succeeded), len(result.Failed))
+
+	// 写入清单，重新转换时按自定义表情的尺寸配置处理
+	tracker.phase(PhaseWriting)
+	if err := updateManifest(savePath, customEmojiSet(stickers), result.Succeeded); err != nil {
+		log.Printf("更新贴纸清单失败: %v", err)
+	}
+
+	err = result.finish()
+	tracker.phase(PhaseDone)
+	return result, err
+}
@@ -0,0 +1,203 @@
+package sticker
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"mymeme/memeFile/i18n"
+)
+
+// manifestFileName 贴纸集合清单文件名
+const manifestFileName = "sticker.json"
+
+// StickerManifest 贴纸集合清单，记录文件夹对应的贴纸集合和已下载的贴纸
+type StickerManifest struct {
+	Title       string   `json:"title"`
+	Name        string   `json:"name"`
+	Icon        *string  `json:"icon"`
+	URL         string   `json:"url"`
+	StickerType string   `json:"stickerType,omitempty"`
+	Stickers    []string `json:"stickers,omitempty"` // 已下载贴纸的 file_unique_id
+
+	// Outputs 贴纸 file_unique_id -> 文件夹中的输出文件名，文件被重命名后同步更新，重新转换时写回该文件
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ReadManifest 读取文件夹中的贴纸清单
+func ReadManifest(folderPath string) (*StickerManifest, error) {
+	data, err := os.ReadFile(filepath.Join(folderPath, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest StickerManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrParseManifest)
+	}
+	return &manifest, nil
+}
+
+// writeManifest 写入文件夹中的贴纸清单
+func writeManifest(folderPath string, manifest *StickerManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(folderPath, manifestFileName), data, 0644)
+}
+
+// outputFileName 查找贴纸以 file_unique_id 命名的输出文件，不存在时返回空字符串
+func outputFileName(folderPath string, id string) string {
+	for _, ext := range []string{".gif", ".png", ".webp"} {
+		if _, err := os.Stat(filepath.Join(folderPath, id+ext)); err == nil {
+			return id + ext
+		}
+	}
+	return ""
+}
+
+// RenameManifestOutputs 文件夹中的文件被重命名后同步更新清单中的输出文件名，renames 为 原名称 -> 新名称
+// 旧版本的清单没有输出文件名记录时，按 file_unique_id 命名的文件补充记录
+func RenameManifestOutputs(folderPath string, renames map[string]string) error {
+	manifest, err := ReadManifest(folderPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if manifest.Outputs == nil {
+		manifest.Outputs = make(map[string]string)
+	}
+
+	changed := false
+	for id, name := range manifest.Outputs {
+		if newName, ok := renames[name]; ok {
+			manifest.Outputs[id] = newName
+			changed = true
+		}
+	}
+	for _, id := range manifest.Stickers {
+		if _, ok := manifest.Outputs[id]; ok {
+			continue
+		}
+		for _, ext := range []string{".gif", ".png", ".webp"} {
+			if newName, ok := renames[id+ext]; ok {
+				manifest.Outputs[id] = newName
+				changed = true
+				break
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return writeManifest(folderPath, manifest)
+}
+
+// updateManifest 将下载成功的贴纸合并到文件夹清单中，保留已有的记录
+func updateManifest(folderPath string, set *TelegramStickerSet, downloaded []string) error {
+	manifest, err := ReadManifest(folderPath)
+	if err != nil {
+		manifest = &StickerManifest{}
+	}
+
+	// 清单属于其他贴纸集合时保留原有信息，只追加贴纸记录
+	// 集合名称未知时（如来自不同集合的自定义表情）只记录类型
+	if manifest.Name == "" || manifest.Name == set.Name {
+		if set.Title != "" {
+			manifest.Title = set.Title
+		}
+		if set.Name != "" {
+			manifest.Name = set.Name
+			ref := StickerSetRef{Name: set.Name, Kind: RefKindStickers}
+			if set.StickerType == StickerTypeCustomEmoji {
+				ref.Kind = RefKindEmoji
+			}
+			manifest.URL = ref.URL()
+		}
+		manifest.StickerType = set.StickerType
+	}
+
+	existing := make(map[string]bool, len(manifest.Stickers))
+	for _, id := range manifest.Stickers {
+		existing[id] = true
+	}
+	if manifest.Outputs == nil {
+		manifest.Outputs = make(map[string]string)
+	}
+	for _, id := range downloaded {
+		if !existing[id] {
+			manifest.Stickers = append(manifest.Stickers, id)
+			existing[id] = true
+		}
+		if name := outputFileName(folderPath, id); name != "" {
+			manifest.Outputs[id] = name
+		}
+	}
+
+	return writeManifest(folderPath, manifest)
+}
+
+// DownloadStickers 只下载贴纸集合中指定的贴纸到 savePath，并更新该文件夹的清单
+// 集合中不存在的贴纸ID记为跳过
+func (td *TelegramDownloader) DownloadStickers(stickerSetName string, fileUniqueIDs []string, savePath string, progressCallback func(DownloadProgress)) (*DownloadResult, error) {
+	if len(fileUniqueIDs) == 0 {
+		return nil, i18n.Errorf(i18n.ErrStickerListRequired)
+	}
+
+	result := newDownloadResult(savePath)
+	tracker := newProgressTracker(result, progressCallback)
+
+	tracker.phase(PhaseFetching)
+	set, err := td.GetStickerSet(stickerSetName)
+	if err != nil {
+		return nil, err
+	}
+	result.Name = set.Name
+	result.Title = set.Title
+
+	inSet := make(map[string]TelegramSticker, len(set.Stickers))
+	for _, sticker := range set.Stickers {
+		inSet[sticker.FileUniqueID] = sticker
+	}
+
+	var stickers []TelegramSticker
+	seen := make(map[string]bool, len(fileUniqueIDs))
+	for _, id := range fileUniqueIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if sticker, ok := inSet[id]; ok {
+			stickers = append(stickers, sticker)
+		} else {
+			result.Skipped = append(result.Skipped, id)
+		}
+	}
+	if len(stickers) == 0 {
+		return nil, i18n.Errorf(i18n.ErrStickersNotInSet, stickerSetName)
+	}
+	result.Total = len(stickers)
+
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrCreateDir)
+	}
+
+	tracker.phase(PhaseDownloading)
+	td.downloadStickers(stickers, savePath, tracker)
+
+	// 贴纸已经写入，清单更新失败不影响下载结果
+	tracker.phase(PhaseWriting)
+	if err := updateManifest(savePath, set, result.Succeeded); err != nil {
+		log.Printf("更新贴纸清单失败: %v", err)
+	}
+
+	err = result.finish()
+	tracker.phase(PhaseDone)
+	return result, err
+}
@@ -0,0 +1,134 @@
+package sticker
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"mymeme/memeFile/i18n"
+)
+
+// thumbCacheDirName 缩略图缓存目录名，位于系统临时目录下
+const thumbCacheDirName = "QQmeme/thumbs"
+
+// defaultPreviewLimit 默认预览的贴纸数量
+const defaultPreviewLimit = 20
+
+// StickerPreview 单个贴纸的预览信息
+type StickerPreview struct {
+	FileUniqueID string `json:"fileUniqueId"` // 贴纸唯一ID，用于选择性下载
+	Emoji        string `json:"emoji"`        // 关联的 emoji
+	Type         string `json:"type"`         // 贴纸类型
+	IsAnimated   bool   `json:"isAnimated"`   // 是否为 TGS 动画
+	IsVideo      bool   `json:"isVideo"`      // 是否为 WebM 视频
+	Thumbnail    string `json:"thumbnail"`    // 缩略图本地路径，无缩略图时为空
+}
+
+// StickerSetInfo 贴纸集合的预览信息
+type StickerSetInfo struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	StickerType string           `json:"stickerType"`
+	IsAnimated  bool             `json:"isAnimated"`
+	IsVideo     bool             `json:"isVideo"`
+	Count       int              `json:"count"`    // 贴纸总数
+	Stickers    []StickerPreview `json:"stickers"` // 前 N 个贴纸的预览
+}
+
+// ThumbCacheDir 获取缩略图缓存目录
+func ThumbCacheDir() string {
+	return filepath.Join(os.TempDir(), thumbCacheDirName)
+}
+
+// GetStickerSetInfo 获取贴纸集合信息和前 limit 个贴纸的缩略图，不下载贴纸本身
+func (td *TelegramDownloader) GetStickerSetInfo(stickerSetName string, limit int) (*StickerSetInfo, error) {
+	set, err := td.GetStickerSet(stickerSetName)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultPreviewLimit
+	}
+	limit = min(limit, len(set.Stickers))
+
+	info := &StickerSetInfo{
+		Name:        set.Name,
+		Title:       set.Title,
+		Description: set.Description,
+		StickerType: set.StickerType,
+		IsAnimated:  set.IsAnimated,
+		IsVideo:     set.IsVideo,
+		Count:       len(set.Stickers),
+		Stickers:    make([]StickerPreview, limit),
+	}
+
+	cacheDir := ThumbCacheDir()
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrThumbCacheDir)
+	}
+
+	semaphore := make(chan struct{}, 6)
+	var wg sync.WaitGroup
+
+	for i, sticker := range set.Stickers[:limit] {
+		info.Stickers[i] = StickerPreview{
+			FileUniqueID: sticker.FileUniqueID,
+			Emoji:        sticker.Emoji,
+			Type:         sticker.Type,
+			IsAnimated:   sticker.IsAnimated,
+			IsVideo:      sticker.IsVideo,
+		}
+
+		if sticker.Thumbnail == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int, thumb TelegramPhotoSize) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			path, err := td.cacheThumbnail(cacheDir, thumb)
+			if err != nil {
+				log.Printf("下载缩略图失败 %s: %v", thumb.FileUniqueID, err)
+				return
+			}
+			info.Stickers[index].Thumbnail = path
+		}(i, *sticker.Thumbnail)
+	}
+
+	wg.Wait()
+	return info, nil
+}
+
+// cacheThumbnail 下载缩略图到缓存目录，已缓存时直接返回路径
+func (td *TelegramDownloader) cacheThumbnail(cacheDir string, thumb TelegramPhotoSize) (string, error) {
+	// 缩略图通常为 webp，也可能是 jpg
+	for _, ext := range []string{".webp", ".jpg"} {
+		path := filepath.Join(cacheDir, thumb.FileUniqueID+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	data, filePath, err := td.fetchFile(thumb.FileID)
+	if err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(filePath)
+	if ext == "" {
+		ext = ".webp"
+	}
+
+	path := filepath.Join(cacheDir, thumb.FileUniqueID+ext)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", i18n.Wrap(err, i18n.ErrWriteThumb)
+	}
+	return path, nil
+}
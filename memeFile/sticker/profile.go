@@ -0,0 +1,332 @@
+package sticker
+
+import (
+	"image/color"
+	"math"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 动图输出格式
+const (
+	FormatGIF  = "gif"  // GIF，1 位透明
+	FormatAPNG = "apng" // APNG，完整 alpha 通道，扩展名为 .png
+	FormatWebP = "webp" // 动态 WebP，完整 alpha 通道
+)
+
+// ConversionProfile 贴纸转换配置
+type ConversionProfile struct {
+	ID         string  `json:"id"`         // 配置唯一标识
+	Name       string  `json:"name"`       // 显示名称
+	MaxSize    int     `json:"maxSize"`    // 动图输出最长边（像素），0 表示使用原始尺寸的一半
+	StaticSize int     `json:"staticSize"` // 静态贴纸输出宽度（像素），0 表示保持原始尺寸
+	FPS        float64 `json:"fps"`        // TGS 目标帧率，0 表示按原始帧率自动选择 16/20/24 帧（精确模式下为渲染全部源帧）
+	Accurate   bool    `json:"accurate"`   // 精确模式：渲染全部源帧或按目标帧率重采样，保持原始时长
+	FrameCap   int     `json:"frameCap"`   // TGS 最大输出帧数，0 表示不限制；WebM 贴纸始终保留全部帧
+	MinDelay   int     `json:"minDelay"`   // 每帧最小延迟（1/100 秒）
+	MaxDelay   int     `json:"maxDelay"`   // 每帧最大延迟（1/100 秒）
+	Loop       int     `json:"loop"`       // 播放次数，0 表示无限循环
+	Colors     int     `json:"colors"`     // 调色板颜色数量（2-256）
+	Dither     bool    `json:"dither"`     // 是否使用抖动
+	Format     string  `json:"format"`     // 动图输出格式
+	EmojiSize  int     `json:"emojiSize"`  // 自定义表情输出尺寸（像素），0 表示 100
+
+	Quantizer      string `json:"quantizer"`      // 调色板生成方式：plan9 / mediancut
+	PaletteScope   string `json:"paletteScope"`   // 调色板作用范围：animation / frame
+	AlphaThreshold int    `json:"alphaThreshold"` // 低于该值的像素视为透明（1-255）
+	Matte          string `json:"matte"`          // 半透明边缘混合的背景色（#RRGGBB），为空时还原原始颜色
+}
+
+// 预设转换配置
+var (
+	// DefaultProfile 默认配置，TGS 按时长自动选择 16/20/24 帧，WebM 保留原始帧
+	DefaultProfile = ConversionProfile{
+		ID:         "default",
+		Name:       "默认",
+		MaxSize:    0,
+		StaticSize: 512,
+		FPS:        0,
+		FrameCap:   24,
+		MinDelay:   2,
+		MaxDelay:   20,
+		Loop:       0,
+		Colors:     256,
+		Dither:     true,
+		Format:     FormatGIF,
+
+		Quantizer:      QuantizerPlan9,
+		PaletteScope:   PaletteScopeAnimation,
+		AlphaThreshold: 128,
+	}
+
+	// QQSmallProfile 体积较小，适合在 QQ 中直接发送
+	QQSmallProfile = ConversionProfile{
+		ID:         "qq_small",
+		Name:       "QQ 小图",
+		MaxSize:    160,
+		StaticSize: 240,
+		FPS:        0,
+		FrameCap:   16,
+		MinDelay:   4,
+		MaxDelay:   20,
+		Loop:       0,
+		Colors:     128,
+		Dither:     true,
+		Format:     FormatGIF,
+
+		Quantizer:      QuantizerMedianCut,
+		PaletteScope:   PaletteScopeAnimation,
+		AlphaThreshold: 128,
+	}
+
+	// HighQualityProfile 高分辨率、高帧率
+	HighQualityProfile = ConversionProfile{
+		ID:         "high_quality",
+		Name:       "高质量",
+		MaxSize:    512,
+		StaticSize: 512,
+		FPS:        50,
+		Accurate:   true,
+		FrameCap:   0,
+		MinDelay:   2,
+		MaxDelay:   20,
+		Loop:       0,
+		Colors:     256,
+		Dither:     true,
+		Format:     FormatGIF,
+
+		Quantizer:      QuantizerMedianCut,
+		PaletteScope:   PaletteScopeAnimation,
+		AlphaThreshold: 128,
+	}
+
+	// AccurateProfile 渲染全部源帧，时长与原动画一致
+	AccurateProfile = ConversionProfile{
+		ID:         "accurate",
+		Name:       "原始帧率",
+		MaxSize:    0,
+		StaticSize: 512,
+		FPS:        0,
+		Accurate:   true,
+		FrameCap:   0,
+		MinDelay:   2,
+		MaxDelay:   20,
+		Loop:       0,
+		Colors:     256,
+		Dither:     true,
+		Format:     FormatGIF,
+
+		Quantizer:      QuantizerMedianCut,
+		PaletteScope:   PaletteScopeAnimation,
+		AlphaThreshold: 128,
+	}
+
+	// APNGProfile 输出带完整透明度的 APNG，深色背景下边缘更平滑
+	APNGProfile = ConversionProfile{
+		ID:         "apng",
+		Name:       "APNG 透明",
+		MaxSize:    0,
+		StaticSize: 512,
+		FPS:        0,
+		Accurate:   true,
+		FrameCap:   0,
+		MinDelay:   2,
+		MaxDelay:   20,
+		Loop:       0,
+		Colors:     256,
+		Dither:     false,
+		Format:     FormatAPNG,
+
+		Quantizer:      QuantizerPlan9,
+		PaletteScope:   PaletteScopeAnimation,
+		AlphaThreshold: 128,
+	}
+)
+
+// PresetProfiles 返回所有预设配置
+func PresetProfiles() []ConversionProfile {
+	return []ConversionProfile{DefaultProfile, QQSmallProfile, HighQualityProfile, AccurateProfile, APNGProfile}
+}
+
+// IsPresetProfile 判断 ID 是否为预设配置
+func IsPresetProfile(id string) bool {
+	for _, p := range PresetProfiles() {
+		if p.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 检查配置是否合法
+func (p ConversionProfile) Validate() error {
+	if p.ID == "" {
+		return i18n.Errorf(i18n.ErrProfileIDRequired)
+	}
+	if p.MaxSize < 0 || p.MaxSize > 2048 {
+		return i18n.Errorf(i18n.ErrMaxSizeRange, p.MaxSize)
+	}
+	if p.StaticSize < 0 || p.StaticSize > 2048 {
+		return i18n.Errorf(i18n.ErrStaticSizeRange, p.StaticSize)
+	}
+	if p.FPS < 0 || p.FPS > 100 {
+		return i18n.Errorf(i18n.ErrFPSRange, p.FPS)
+	}
+	if p.EmojiSize < 0 || p.EmojiSize > 512 {
+		return i18n.Errorf(i18n.ErrEmojiSizeRange, p.EmojiSize)
+	}
+	if p.FrameCap < 0 {
+		return i18n.Errorf(i18n.ErrFrameCapNegative)
+	}
+	if p.Colors != 0 && (p.Colors < 2 || p.Colors > 256) {
+		return i18n.Errorf(i18n.ErrColorsRange, p.Colors)
+	}
+	switch p.Format {
+	case "", FormatGIF, FormatAPNG, FormatWebP:
+	default:
+		return i18n.Errorf(i18n.ErrUnsupportedFormat, p.Format)
+	}
+	switch p.Quantizer {
+	case "", QuantizerPlan9, QuantizerMedianCut:
+	default:
+		return i18n.Errorf(i18n.ErrUnsupportedQuantizer, p.Quantizer)
+	}
+	switch p.PaletteScope {
+	case "", PaletteScopeAnimation, PaletteScopeFrame:
+	default:
+		return i18n.Errorf(i18n.ErrUnsupportedScope, p.PaletteScope)
+	}
+	if p.AlphaThreshold < 0 || p.AlphaThreshold > 255 {
+		return i18n.Errorf(i18n.ErrAlphaThresholdRange, p.AlphaThreshold)
+	}
+	if _, err := parseMatte(p.Matte); err != nil {
+		return err
+	}
+	return nil
+}
+
+// normalize 填充缺省值并修正不合理的参数
+func (p ConversionProfile) normalize() ConversionProfile {
+	if p.Colors < 2 || p.Colors > 256 {
+		p.Colors = 256
+	}
+	if p.MinDelay < 2 {
+		p.MinDelay = 2 // 多数客户端会将小于 2/100 秒的延迟当作 10/100 秒处理
+	}
+	if p.MaxDelay < p.MinDelay {
+		p.MaxDelay = p.MinDelay
+	}
+	if p.Format == "" {
+		p.Format = FormatGIF
+	}
+	if p.EmojiSize <= 0 {
+		p.EmojiSize = 100
+	}
+	if p.Quantizer == "" {
+		p.Quantizer = QuantizerPlan9
+	}
+	if p.PaletteScope == "" {
+		p.PaletteScope = PaletteScopeAnimation
+	}
+	if p.AlphaThreshold <= 0 || p.AlphaThreshold > 255 {
+		p.AlphaThreshold = 128
+	}
+	return p
+}
+
+// emojiProfile 返回用于自定义表情的配置，动图和静态图都缩放到 EmojiSize
+func (p ConversionProfile) emojiProfile() ConversionProfile {
+	p.MaxSize = p.EmojiSize
+	p.StaticSize = p.EmojiSize
+	return p
+}
+
+// animatedExt 返回动图输出文件的扩展名
+func (p ConversionProfile) animatedExt() string {
+	switch p.Format {
+	case FormatAPNG:
+		return ".png"
+	case FormatWebP:
+		return ".webp"
+	default:
+		return ".gif"
+	}
+}
+
+// animatedSize 根据配置计算动图输出尺寸
+func (p ConversionProfile) animatedSize(width, height int) (int, int) {
+	if width <= 0 || height <= 0 {
+		width, height = 512, 512
+	}
+
+	if p.MaxSize <= 0 {
+		return max(width/2, 1), max(height/2, 1)
+	}
+
+	scale := float64(p.MaxSize) / float64(max(width, height))
+	return max(int(math.Round(float64(width)*scale)), 1), max(int(math.Round(float64(height)*scale)), 1)
+}
+
+// frameCount 根据配置计算 TGS 输出帧数
+func (p ConversionProfile) frameCount(frameRate, duration float64, originalTotalFrames int) int {
+	var totalFrames int
+
+	if p.FPS > 0 && duration > 0 {
+		totalFrames = int(math.Round(duration * p.FPS))
+		if totalFrames > originalTotalFrames {
+			totalFrames = originalTotalFrames
+		}
+	} else if frameRate > 0 {
+		// 如果原始帧率很高，适当降低但保持流畅
+		if frameRate >= 30 {
+			totalFrames = 24 // 高帧率动画用24帧
+		} else if frameRate >= 15 {
+			totalFrames = 20 // 中等帧率用20帧
+		} else {
+			totalFrames = 16 // 低帧率用16帧
+		}
+	} else {
+		// 如果无法获取原始帧率，根据时长判断
+		if duration <= 2.0 {
+			totalFrames = 24
+		} else if duration <= 5.0 {
+			totalFrames = 20
+		} else {
+			totalFrames = 16
+		}
+	}
+
+	if p.FrameCap > 0 && totalFrames > p.FrameCap {
+		totalFrames = p.FrameCap
+	}
+	if totalFrames < 1 {
+		totalFrames = 1
+	}
+	return totalFrames
+}
+
+// gifLoopCount 将播放次数转换为 GIF 的 LoopCount（0 无限循环，-1 只播放一次）
+func (p ConversionProfile) gifLoopCount() int {
+	if p.Loop <= 0 {
+		return 0
+	}
+	if p.Loop == 1 {
+		return -1
+	}
+	return p.Loop - 1
+}
+
+// palette 根据颜色数量从预定义调色板中均匀取色，索引 0 保留为透明色
+func (p ConversionProfile) palette() color.Palette {
+	if p.Colors >= len(globalPalette) {
+		return globalPalette
+	}
+
+	opaque := globalPalette[1:]
+	n := p.Colors - 1
+	pal := color.Palette{color.Transparent}
+	for i := 0; i < n; i++ {
+		pal = append(pal, opaque[i*(len(opaque)-1)/max(n-1, 1)])
+	}
+	return pal
+}
@@ -0,0 +1,227 @@
+package sticker
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/platform"
+)
+
+// ProxyAuto 代理地址为空或为该值时，依次使用环境变量和系统代理设置
+const ProxyAuto = "auto"
+
+// telegramAPIBase Telegram Bot API 地址
+const telegramAPIBase = "https://api.telegram.org"
+
+// 代理协议默认端口
+var defaultProxyPorts = map[string]string{
+	"http":   "80",
+	"https":  "443",
+	"socks5": "1080",
+}
+
+// ProxyTestResult 结构体 - 代理连通性测试结果
+type ProxyTestResult struct {
+	OK         bool   `json:"ok"`         // 是否能访问 Telegram API
+	Proxy      string `json:"proxy"`      // 实际使用的代理（隐藏密码），为空表示直连
+	StatusCode int    `json:"statusCode"` // HTTP 状态码
+	LatencyMs  int64  `json:"latencyMs"`  // 请求耗时（毫秒）
+	Error      string `json:"error"`      // 失败原因
+}
+
+// ParseProxyURL 校验并规范化代理地址
+// 支持 http、https、socks5、socks5h，可带用户名密码；未写协议时按 http 处理，未写端口时使用协议默认端口
+func ParseProxyURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, i18n.Errorf(i18n.ErrProxyRequired)
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, i18n.Errorf(i18n.ErrProxyInvalid, redactProxy(raw))
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	case "socks5h":
+		// net/http 的 SOCKS5 代理总是由代理服务器解析域名，与 socks5h 行为一致
+		u.Scheme = "socks5"
+	default:
+		return nil, i18n.Errorf(i18n.ErrProxyScheme, u.Scheme)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil, i18n.Errorf(i18n.ErrProxyHost, redactProxy(raw))
+	}
+	if port := u.Port(); port == "" {
+		u.Host = net.JoinHostPort(host, defaultProxyPorts[u.Scheme])
+	} else if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+		return nil, i18n.Errorf(i18n.ErrProxyPort, port)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return nil, i18n.Errorf(i18n.ErrProxyPath, redactProxy(raw))
+	}
+	u.Path = ""
+
+	return u, nil
+}
+
+// resolveProxy 确定实际使用的代理地址和不使用代理的主机列表
+// needProxy 为 false 时直连；proxyURL 为空或为 auto 时依次读取 HTTPS_PROXY/HTTP_PROXY/ALL_PROXY 环境变量和系统代理
+func resolveProxy(proxyURL string, needProxy bool) (*url.URL, string, error) {
+	if !needProxy {
+		return nil, "", nil
+	}
+
+	raw := strings.TrimSpace(proxyURL)
+	noProxy := getenv("NO_PROXY", "no_proxy")
+
+	if raw == "" || strings.EqualFold(raw, ProxyAuto) {
+		raw = getenv("HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "ALL_PROXY", "all_proxy")
+		if raw == "" {
+			system := platform.SystemProxy()
+			raw = system.URL
+			if noProxy == "" {
+				noProxy = system.Bypass
+			}
+		}
+		if raw == "" {
+			return nil, "", nil
+		}
+	}
+
+	u, err := ParseProxyURL(raw)
+	if err != nil {
+		return nil, "", err
+	}
+	return u, noProxy, nil
+}
+
+// newProxyTransport 创建使用代理的 Transport，NO_PROXY 中的主机直连
+func newProxyTransport(proxyURL string, needProxy bool) (*http.Transport, *url.URL, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+
+	proxy, noProxy, err := resolveProxy(proxyURL, needProxy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proxy == nil {
+		return transport, nil, nil
+	}
+
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxy, nil
+	}
+	return transport, proxy, nil
+}
+
+// bypassProxy 判断主机是否在 NO_PROXY 列表中
+// 支持 *、完整主机名、.example.com / example.com 后缀匹配、IP 和 CIDR
+func bypassProxy(host, noProxy string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		entry = strings.TrimPrefix(entry, ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// getenv 返回第一个非空的环境变量
+func getenv(names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// redactProxy 隐藏代理地址中的密码
+func redactProxy(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// TestProxy 测试能否通过当前代理设置访问 Telegram API
+// 已设置 Bot Token 时调用 getMe，同时检查 Token 是否有效
+func (td *TelegramDownloader) TestProxy() ProxyTestResult {
+	result := ProxyTestResult{}
+	if td.proxy != nil {
+		result.Proxy = td.proxy.Redacted()
+	}
+
+	apiURL := telegramAPIBase
+	if td.botToken != "" {
+		apiURL = fmt.Sprintf("%s/bot%s/getMe", telegramAPIBase, td.botToken)
+	}
+
+	start := time.Now()
+	resp, err := td.get(apiURL)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	switch {
+	case td.botToken != "" && resp.StatusCode == http.StatusUnauthorized:
+		result.Error = i18n.T(i18n.ErrBotTokenRejected)
+	case td.botToken != "" && resp.StatusCode != http.StatusOK:
+		result.Error = i18n.T(i18n.ErrAPIStatus, resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		result.Error = i18n.T(i18n.ErrAPIStatus, resp.StatusCode)
+	default:
+		result.OK = true
+	}
+	return result
+}
@@ -0,0 +1,236 @@
+package sticker
+
+import (
+	"image"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 调色板生成方式
+const (
+	QuantizerPlan9     = "plan9"     // 固定的 Plan9 调色板
+	QuantizerMedianCut = "mediancut" // 中位切分自适应调色板
+)
+
+// 调色板作用范围
+const (
+	PaletteScopeAnimation = "animation" // 整个动画共用一个调色板
+	PaletteScopeFrame     = "frame"     // 每帧单独生成调色板
+)
+
+// histBits 颜色直方图每个通道保留的位数
+const histBits = 5
+
+// colorBox 中位切分中的颜色盒子，包含若干直方图桶
+type colorBox struct {
+	buckets []int // 直方图桶索引
+	count   int   // 盒子内像素总数
+}
+
+// colorHistogram 量化后的颜色直方图
+type colorHistogram struct {
+	count [1 << (histBits * 3)]int
+	sumR  [1 << (histBits * 3)]int
+	sumG  [1 << (histBits * 3)]int
+	sumB  [1 << (histBits * 3)]int
+}
+
+// add 将图像中的不透明像素加入直方图
+func (h *colorHistogram) add(rgba *image.RGBA) {
+	pix := rgba.Pix
+	for i := 0; i+3 < len(pix); i += 4 {
+		if pix[i+3] == 0 {
+			continue
+		}
+		r, g, b := int(pix[i]), int(pix[i+1]), int(pix[i+2])
+		idx := (r>>(8-histBits))<<(histBits*2) | (g>>(8-histBits))<<histBits | b>>(8-histBits)
+		h.count[idx]++
+		h.sumR[idx] += r
+		h.sumG[idx] += g
+		h.sumB[idx] += b
+	}
+}
+
+// channel 返回桶在指定通道上的量化值
+func (h *colorHistogram) channel(bucket, ch int) int {
+	return (bucket >> (histBits * (2 - ch))) & (1<<histBits - 1)
+}
+
+// palette 使用中位切分生成最多 n 种不透明颜色，索引 0 保留为透明色
+func (h *colorHistogram) palette(n int) color.Palette {
+	var all colorBox
+	for i, c := range h.count {
+		if c > 0 {
+			all.buckets = append(all.buckets, i)
+			all.count += c
+		}
+	}
+
+	pal := color.Palette{color.Transparent}
+	if all.count == 0 {
+		return append(pal, color.RGBA{A: 255})
+	}
+
+	boxes := []colorBox{all}
+	for len(boxes) < n-1 {
+		// 选择像素最多且可继续切分的盒子
+		target := -1
+		for i, box := range boxes {
+			if len(box.buckets) > 1 && (target < 0 || box.count > boxes[target].count) {
+				target = i
+			}
+		}
+		if target < 0 {
+			break
+		}
+
+		a, b := h.split(boxes[target])
+		boxes[target] = a
+		boxes = append(boxes, b)
+	}
+
+	for _, box := range boxes {
+		var r, g, b int
+		for _, bucket := range box.buckets {
+			r += h.sumR[bucket]
+			g += h.sumG[bucket]
+			b += h.sumB[bucket]
+		}
+		pal = append(pal, color.RGBA{
+			R: uint8(r / box.count),
+			G: uint8(g / box.count),
+			B: uint8(b / box.count),
+			A: 255,
+		})
+	}
+	return pal
+}
+
+// split 沿范围最大的通道在像素中位数处切分盒子
+func (h *colorHistogram) split(box colorBox) (colorBox, colorBox) {
+	ch, widest := 0, -1
+	for c := 0; c < 3; c++ {
+		lo, hi := 1<<histBits, -1
+		for _, bucket := range box.buckets {
+			v := h.channel(bucket, c)
+			lo = min(lo, v)
+			hi = max(hi, v)
+		}
+		if hi-lo > widest {
+			ch, widest = c, hi-lo
+		}
+	}
+
+	sort.Slice(box.buckets, func(i, j int) bool {
+		return h.channel(box.buckets[i], ch) < h.channel(box.buckets[j], ch)
+	})
+
+	half, acc, cut := box.count/2, 0, 1
+	for i, bucket := range box.buckets[:len(box.buckets)-1] {
+		acc += h.count[bucket]
+		cut = i + 1
+		if acc >= half {
+			break
+		}
+	}
+
+	left := colorBox{buckets: box.buckets[:cut:cut]}
+	right := colorBox{buckets: box.buckets[cut:]}
+	for _, bucket := range left.buckets {
+		left.count += h.count[bucket]
+	}
+	right.count = box.count - left.count
+	return left, right
+}
+
+// flattenAlpha 处理半透明像素，使每个像素要么完全透明，要么完全不透明
+//
+// rlottie 输出的是预乘 alpha 的颜色，直接量化会让抗锯齿边缘发黑。
+// 低于阈值的像素视为透明；其余像素在指定背景色时与背景混合，否则去预乘还原原始颜色。
+func flattenAlpha(rgba *image.RGBA, threshold uint8, matte *color.RGBA) {
+	pix := rgba.Pix
+	for i := 0; i+3 < len(pix); i += 4 {
+		a := pix[i+3]
+		if a == 255 {
+			continue
+		}
+		if a < threshold || a == 0 {
+			pix[i], pix[i+1], pix[i+2], pix[i+3] = 0, 0, 0, 0
+			continue
+		}
+
+		for c := 0; c < 3; c++ {
+			v := int(pix[i+c])
+			if matte != nil {
+				// 预乘颜色与背景混合: c + bg * (1 - a)
+				var bg int
+				switch c {
+				case 0:
+					bg = int(matte.R)
+				case 1:
+					bg = int(matte.G)
+				default:
+					bg = int(matte.B)
+				}
+				v += bg * (255 - int(a)) / 255
+			} else {
+				v = v * 255 / int(a)
+			}
+			pix[i+c] = uint8(min(v, 255))
+		}
+		pix[i+3] = 255
+	}
+}
+
+// parseMatte 解析 #RRGGBB 格式的背景色，空字符串表示不混合背景
+func parseMatte(s string) (*color.RGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if s == "" {
+		return nil, nil
+	}
+	if len(s) != 6 {
+		return nil, i18n.Errorf(i18n.ErrInvalidMatte, s)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return nil, i18n.Errorf(i18n.ErrInvalidMatte, s)
+	}
+	return &color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+// buildPalettes 根据配置为每一帧生成调色板
+func (p ConversionProfile) buildPalettes(frames []*image.RGBA) []color.Palette {
+	palettes := make([]color.Palette, len(frames))
+
+	if p.Quantizer != QuantizerMedianCut {
+		pal := p.palette()
+		for i := range palettes {
+			palettes[i] = pal
+		}
+		return palettes
+	}
+
+	if p.PaletteScope == PaletteScopeFrame {
+		for i, frame := range frames {
+			var hist colorHistogram
+			hist.add(frame)
+			palettes[i] = hist.palette(p.Colors)
+		}
+		return palettes
+	}
+
+	var hist colorHistogram
+	for _, frame := range frames {
+		hist.add(frame)
+	}
+	pal := hist.palette(p.Colors)
+	for i := range palettes {
+		palettes[i] = pal
+	}
+	return palettes
+}
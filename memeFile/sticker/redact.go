@@ -0,0 +1,55 @@
+package sticker
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// botTokenPattern 匹配 Telegram Bot Token（<Bot ID>:<密钥>）
+var botTokenPattern = regexp.MustCompile(`\d{5,}:[A-Za-z0-9_-]{30,}`)
+
+// redactedToken 替换 Token 的占位文本
+const redactedToken = "<bot-token>"
+
+// RedactToken 将字符串中形如 Bot Token 的内容替换为占位文本
+func RedactToken(s string) string {
+	return botTokenPattern.ReplaceAllString(s, redactedToken)
+}
+
+// redactWriter 写入前隐藏 Bot Token 的 io.Writer
+type redactWriter struct {
+	w io.Writer
+}
+
+// NewRedactWriter 创建写入前隐藏 Bot Token 的 io.Writer，用于 log.SetOutput
+func NewRedactWriter(w io.Writer) io.Writer {
+	return redactWriter{w: w}
+}
+
+func (rw redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, RedactToken(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// redact 隐藏字符串中的 Token，包括格式不标准的当前 Token
+func (td *TelegramDownloader) redact(s string) string {
+	if td.botToken != "" {
+		s = strings.ReplaceAll(s, td.botToken, redactedToken)
+	}
+	return RedactToken(s)
+}
+
+// get 发送 GET 请求，请求失败时返回的错误中不包含 Token
+// net/http 的错误信息会带上完整的请求地址，而 Bot API 的地址中包含 Token
+func (td *TelegramDownloader) get(rawURL string) (*http.Response, error) {
+	resp, err := td.client.Get(rawURL)
+	if err != nil {
+		return nil, errors.New(td.redact(err.Error()))
+	}
+	return resp, nil
+}
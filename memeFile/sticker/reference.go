@@ -0,0 +1,118 @@
+package sticker
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 贴纸引用类型
+const (
+	RefKindStickers = "stickers" // 普通贴纸集合（addstickers）
+	RefKindEmoji    = "emoji"    // 自定义表情集合（addemoji）
+)
+
+// stickerSetNamePattern 贴纸集合名称语法：1-64 个英文字母、数字或下划线
+var stickerSetNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// telegramHosts 可用于贴纸链接的 Telegram 域名
+var telegramHosts = map[string]bool{
+	"t.me":         true,
+	"www.t.me":     true,
+	"telegram.me":  true,
+	"telegram.dog": true,
+}
+
+// StickerSetRef 解析后的贴纸集合引用
+type StickerSetRef struct {
+	Name string `json:"name"` // 集合名称
+	Kind string `json:"kind"` // 引用类型：stickers / emoji
+}
+
+// URL 返回该集合的 t.me 分享链接
+func (r StickerSetRef) URL() string {
+	path := "addstickers"
+	if r.Kind == RefKindEmoji {
+		path = "addemoji"
+	}
+	return fmt.Sprintf("https://t.me/%s/%s", path, url.PathEscape(r.Name))
+}
+
+// ParseStickerSetRef 解析各种形式的贴纸集合引用：
+//
+//	https://t.me/addstickers/name、t.me/addstickers/name、telegram.me/addemoji/name
+//	tg://addstickers?set=name、tg://addemoji?set=name
+//	@name、直接输入的名称
+func ParseStickerSetRef(input string) (StickerSetRef, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return StickerSetRef{}, i18n.Errorf(i18n.ErrStickerRefRequired)
+	}
+
+	ref := StickerSetRef{Kind: RefKindStickers}
+
+	switch {
+	case strings.HasPrefix(strings.ToLower(s), "tg://"):
+		u, err := url.Parse(s)
+		if err != nil {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrInvalidLink, input)
+		}
+		kind, ok := refKind(u.Host)
+		if !ok {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrUnsupportedTgLink, input)
+		}
+		ref.Kind = kind
+		ref.Name = u.Query().Get("set")
+
+	case strings.HasPrefix(s, "@"):
+		ref.Name = s[1:]
+
+	case strings.Contains(s, "/"):
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrInvalidLink, input)
+		}
+		if !telegramHosts[strings.ToLower(u.Hostname())] {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrNotStickerLink, input)
+		}
+
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) != 2 {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrNotStickerLink, input)
+		}
+		kind, ok := refKind(parts[0])
+		if !ok {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrNotStickerLink, input)
+		}
+		ref.Kind = kind
+		ref.Name, err = url.PathUnescape(parts[1])
+		if err != nil {
+			return StickerSetRef{}, i18n.Errorf(i18n.ErrInvalidLink, input)
+		}
+
+	default:
+		ref.Name = s
+	}
+
+	if !stickerSetNamePattern.MatchString(ref.Name) {
+		return StickerSetRef{}, i18n.Errorf(i18n.ErrInvalidSetName, ref.Name)
+	}
+	return ref, nil
+}
+
+// refKind 根据链接路径判断引用类型
+func refKind(action string) (string, bool) {
+	switch strings.ToLower(action) {
+	case "addstickers":
+		return RefKindStickers, true
+	case "addemoji":
+		return RefKindEmoji, true
+	}
+	return "", false
+}
@@ -0,0 +1,177 @@
+package sticker
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	rlottie "github.com/yazmeyaa/go-rlottie"
+)
+
+// defaultRenderBudget 所有 TGS 转换共享的默认内存预算（字节）
+const defaultRenderBudget = 512 << 20
+
+// maxRenderWorkers 单个动画的最大渲染协程数
+const maxRenderWorkers = 4
+
+// disableLottieCache 只需配置一次 rlottie 缓存
+var disableLottieCache sync.Once
+
+// renderBudget 全局渲染内存预算，由所有并发的转换共享
+var renderBudget = newMemoryBudget(defaultRenderBudget)
+
+// SetRenderMemoryBudget 设置所有 TGS 转换共享的内存预算（字节）
+func SetRenderMemoryBudget(limit int64) {
+	renderBudget.setLimit(limit)
+}
+
+// memoryBudget 按字节计数的内存预算，超出时阻塞等待
+type memoryBudget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+func newMemoryBudget(limit int64) *memoryBudget {
+	b := &memoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// setLimit 修改预算上限，并唤醒等待中的转换
+func (b *memoryBudget) setLimit(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if limit > 0 {
+		b.limit = limit
+	}
+	b.cond.Broadcast()
+}
+
+// acquire 申请 n 字节，返回实际申请的字节数，释放时需传入该值
+// 单次申请超过上限时按上限计算，保证大动画也能独占预算完成转换
+func (b *memoryBudget) acquire(n int64) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.limit {
+		n = b.limit
+	}
+	for b.used+n > b.limit {
+		b.cond.Wait()
+	}
+	b.used += n
+	return n
+}
+
+// release 释放 acquire 申请的字节
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used -= n
+	b.cond.Broadcast()
+}
+
+// framePool 复用帧缓冲区，避免每帧分配新的大块内存
+var framePool sync.Pool
+
+// getFrameBuffer 从缓冲池取出至少 size 字节的缓冲区
+func getFrameBuffer(size int) []byte {
+	if v := framePool.Get(); v != nil {
+		buf := *v.(*[]byte)
+		if cap(buf) >= size {
+			return buf[:size]
+		}
+	}
+	return make([]byte, size)
+}
+
+// putFrameBuffer 将缓冲区放回缓冲池
+func putFrameBuffer(buf []byte) {
+	framePool.Put(&buf)
+}
+
+// releaseFrames 编码完成后将帧缓冲区放回缓冲池
+func releaseFrames(frames []*image.RGBA) {
+	for _, frame := range frames {
+		if frame != nil {
+			putFrameBuffer(frame.Pix)
+		}
+	}
+}
+
+// renderWorkerCount 根据帧数和 CPU 数量计算渲染协程数
+func renderWorkerCount(totalFrames int) int {
+	return max(min(runtime.NumCPU(), maxRenderWorkers, totalFrames), 1)
+}
+
+// renderTGSFrames 按帧计划并行渲染 TGS 动画，返回 RGBA 帧
+// rlottie 的同一个动画实例不能并发渲染，除第一个协程复用 anim 外，其余协程各自加载一份动画
+func (td *TelegramDownloader) renderTGSFrames(jsonData string, key string, anim rlottie.Lottie_Animation, plan framePlan, width, height int, workers int) []*image.RGBA {
+	totalFrames := len(plan.frames)
+	frames := make([]*image.RGBA, totalFrames)
+
+	widthUint := uint(width)
+	heightUint := uint(height)
+	frameSize := width * height * 4
+
+	var next int64 = -1
+	var rendered int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			workerAnim := anim
+			if worker > 0 {
+				workerAnim = rlottie.LottieAnimationFromData(jsonData, fmt.Sprintf("%s_%d", key, worker), "")
+				if workerAnim == nil {
+					return // 加载失败时由其他协程完成剩余帧
+				}
+				defer rlottie.LottieAnimationDestroy(workerAnim)
+			}
+
+			buffer := getFrameBuffer(frameSize)
+			defer putFrameBuffer(buffer)
+
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= totalFrames {
+					return
+				}
+
+				rlottie.LottieAnimationRender(workerAnim, plan.frames[i], buffer, widthUint, heightUint, widthUint*4)
+
+				rgbaBuffer := getFrameBuffer(frameSize)
+				for j := 0; j < len(buffer); j += 4 {
+					// 交换 R 和 B 通道：BGRA -> RGBA
+					rgbaBuffer[j] = buffer[j+2]   // R = B
+					rgbaBuffer[j+1] = buffer[j+1] // G = G
+					rgbaBuffer[j+2] = buffer[j]   // B = R
+					rgbaBuffer[j+3] = buffer[j+3] // A = A
+				}
+
+				frames[i] = &image.RGBA{
+					Pix:    rgbaBuffer,
+					Stride: width * 4,
+					Rect:   image.Rect(0, 0, width, height),
+				}
+
+				if done := atomic.AddInt64(&rendered, 1); done%10 == 0 || done == int64(totalFrames) {
+					log.Printf("TGS 转换进度: %d / %d 帧...", done, totalFrames)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	return frames
+}
@@ -0,0 +1,87 @@
+package sticker
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	rlottie "github.com/yazmeyaa/go-rlottie"
+)
+
+// fixtureStickerCount 基准测试使用的贴纸数量，与一个普通贴纸集合的大小相当
+const fixtureStickerCount = 50
+
+// fixtureLottie 生成第 i 个测试贴纸的 Lottie JSON：旋转的圆角矩形
+// 时长、尺寸和颜色随 i 变化，覆盖 1-3 秒的常见贴纸时长
+func fixtureLottie(i int) string {
+	frames := 60 + (i%5)*30
+	size := 160 + (i%7)*40
+	r, g, b := float64(i%3)/2, float64(i%5)/4, float64(i%7)/6
+	return fmt.Sprintf(`{"v":"5.5.2","fr":60,"ip":0,"op":%[1]d,"w":512,"h":512,"nm":"fixture_%[2]d","ddd":0,"assets":[],
+"layers":[{"ddd":0,"ind":1,"ty":4,"nm":"shape","sr":1,"ao":0,"ip":0,"op":%[1]d,"st":0,"bm":0,
+"ks":{"o":{"a":0,"k":100},"p":{"a":0,"k":[256,256,0]},"a":{"a":0,"k":[0,0,0]},"s":{"a":0,"k":[100,100,100]},
+"r":{"a":1,"k":[{"t":0,"s":[0],"i":{"x":[0.5],"y":[0.5]},"o":{"x":[0.5],"y":[0.5]}},{"t":%[1]d,"s":[360]}]}},
+"shapes":[{"ty":"gr","it":[
+{"ty":"rc","d":1,"s":{"a":0,"k":[%[3]d,%[3]d]},"p":{"a":0,"k":[0,0]},"r":{"a":0,"k":%[4]d}},
+{"ty":"fl","c":{"a":0,"k":[%[5]g,%[6]g,%[7]g,1]},"o":{"a":0,"k":100}},
+{"ty":"tr","p":{"a":0,"k":[0,0]},"a":{"a":0,"k":[0,0]},"s":{"a":0,"k":[100,100]},"r":{"a":0,"k":0},"o":{"a":0,"k":100}}]}]}]}`,
+		frames, i, size, size/8, r, g, b)
+}
+
+// renderFixtureSet 按默认配置渲染所有测试贴纸，workers 为 0 时按 CPU 数量选择协程数
+func renderFixtureSet(b *testing.B, td *TelegramDownloader, fixtures []string, workers int) {
+	for i, jsonData := range fixtures {
+		key := fmt.Sprintf("bench_%d_%d", workers, i)
+		anim := rlottie.LottieAnimationFromData(jsonData, key, "")
+		if anim == nil {
+			b.Fatalf("加载测试贴纸 %d 失败", i)
+		}
+
+		w, h := rlottie.LottieAnimationGetSize(anim)
+		width, height := td.profile.animatedSize(int(w), int(h))
+		plan := td.profile.planFrames(
+			rlottie.LottieAnimationGetFramerate(anim),
+			rlottie.LottieAnimationGetDuration(anim),
+			int(rlottie.LottieAnimationGetTotalframe(anim)))
+
+		n := workers
+		if n == 0 {
+			n = renderWorkerCount(len(plan.frames))
+		}
+		frames := td.renderTGSFrames(jsonData, key, anim, plan, width, height, n)
+		releaseFrames(frames)
+		rlottie.LottieAnimationDestroy(anim)
+	}
+}
+
+// BenchmarkRenderSet 比较多协程渲染和逐帧顺序渲染一个贴纸集合的耗时和内存分配
+func BenchmarkRenderSet(b *testing.B) {
+	// 渲染进度日志会影响计时
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	disableLottieCache.Do(func() {
+		rlottie.LottieConfigureModelCacheSize(0)
+	})
+
+	fixtures := make([]string, fixtureStickerCount)
+	for i := range fixtures {
+		fixtures[i] = fixtureLottie(i)
+	}
+	td := &TelegramDownloader{profile: DefaultProfile}
+
+	b.Run("pool", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			renderFixtureSet(b, td, fixtures, 0)
+		}
+	})
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			renderFixtureSet(b, td, fixtures, 1)
+		}
+	})
+}
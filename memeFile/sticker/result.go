@@ -0,0 +1,148 @@
+package sticker
+
+import (
+	"sync"
+	"time"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 下载进度阶段
+const (
+	PhaseFetching    = "fetching"    // 获取贴纸集合信息
+	PhaseDownloading = "downloading" // 下载贴纸，已下载的贴纸同时开始转换
+	PhaseConverting  = "converting"  // 全部下载完成后等待剩余的转换，或使用保留的原始文件重新转换
+	PhaseWriting     = "writing"     // 写入贴纸清单
+	PhaseDone        = "done"        // 全部完成
+)
+
+// DownloadProgress 结构体 - 下载进度事件，界面文本由前端根据阶段生成
+type DownloadProgress struct {
+	Phase      string `json:"phase"`      // 当前阶段
+	Current    int    `json:"current"`    // 已处理（转换完成或失败）的贴纸数量
+	Downloaded int    `json:"downloaded"` // 已完成下载步骤的贴纸数量
+	Total      int    `json:"total"`      // 贴纸总数
+	Succeeded  int    `json:"succeeded"`  // 成功数量
+	Failed     int    `json:"failed"`     // 失败数量
+	Title      string `json:"title"`      // 贴纸集合标题
+	Item       string `json:"item"`       // 刚处理完的贴纸ID
+	ErrorCode  string `json:"errorCode"`  // 刚处理完的贴纸失败的错误码
+	Error      string `json:"error"`      // 刚处理完的贴纸失败原因
+}
+
+// StickerFailure 结构体 - 处理失败的贴纸及原因
+type StickerFailure struct {
+	ID     string `json:"id"`
+	Code   string `json:"code"`   // 错误码，可用于前端翻译
+	Reason string `json:"reason"` // 当前语言的错误信息
+}
+
+// DownloadResult 结构体 - 一次下载或重新转换的结果
+type DownloadResult struct {
+	Name      string           `json:"name"`      // 贴纸集合名称
+	Title     string           `json:"title"`     // 贴纸集合标题
+	Folder    string           `json:"folder"`    // 保存目录
+	Total     int              `json:"total"`     // 需要处理的贴纸数量
+	Succeeded []string         `json:"succeeded"` // 成功的贴纸ID
+	Failed    []StickerFailure `json:"failed"`    // 失败的贴纸及原因
+	Skipped   []string         `json:"skipped"`   // 跳过的贴纸ID（集合中不存在）
+	ElapsedMs int64            `json:"elapsedMs"` // 耗时（毫秒）
+
+	start time.Time
+}
+
+// newDownloadResult 创建下载结果并开始计时
+func newDownloadResult(folder string) *DownloadResult {
+	return &DownloadResult{
+		Folder:    folder,
+		Succeeded: []string{},
+		Failed:    []StickerFailure{},
+		Skipped:   []string{},
+		start:     time.Now(),
+	}
+}
+
+// finish 记录耗时，全部贴纸都失败时返回错误
+func (r *DownloadResult) finish() error {
+	r.ElapsedMs = time.Since(r.start).Milliseconds()
+
+	if r.Total > 0 && len(r.Succeeded) == 0 && len(r.Failed) > 0 {
+		return i18n.Errorf(i18n.ErrAllStickersFailed, len(r.Failed), r.Failed[0].Reason)
+	}
+	return nil
+}
+
+// progressTracker 汇总并发处理的结果并发送进度事件
+type progressTracker struct {
+	mu       sync.Mutex
+	callback func(DownloadProgress)
+	result   *DownloadResult
+	progress DownloadProgress
+}
+
+// newProgressTracker 创建进度跟踪器，callback 可以为 nil
+func newProgressTracker(result *DownloadResult, callback func(DownloadProgress)) *progressTracker {
+	if callback == nil {
+		callback = func(DownloadProgress) {}
+	}
+	return &progressTracker{callback: callback, result: result}
+}
+
+// phase 进入新的阶段并发送进度事件
+func (t *progressTracker) phase(phase string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Phase = phase
+	t.progress.Total = t.result.Total
+	t.progress.Title = t.result.Title
+	t.progress.Item = ""
+	t.progress.ErrorCode = ""
+	t.progress.Error = ""
+	t.callback(t.progress)
+}
+
+// downloaded 记录一个贴纸完成下载步骤（无论成功与否），全部下载完成后进入转换阶段
+// 每 5 个或进入转换阶段时发送进度事件
+func (t *progressTracker) downloaded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Downloaded++
+	if t.progress.Downloaded == t.progress.Total && t.progress.Phase == PhaseDownloading {
+		t.progress.Phase = PhaseConverting
+		t.progress.Item = ""
+		t.progress.ErrorCode = ""
+		t.progress.Error = ""
+		t.callback(t.progress)
+		return
+	}
+	if t.progress.Downloaded%5 == 0 {
+		t.callback(t.progress)
+	}
+}
+
+// done 记录一个贴纸的处理结果，每 5 个、失败时或最后一个时发送进度事件
+func (t *progressTracker) done(id string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.progress.Current++
+	t.progress.Item = id
+	t.progress.ErrorCode = ""
+	t.progress.Error = ""
+	if err != nil {
+		code := string(i18n.CodeOf(err))
+		t.result.Failed = append(t.result.Failed, StickerFailure{ID: id, Code: code, Reason: err.Error()})
+		t.progress.Failed++
+		t.progress.ErrorCode = code
+		t.progress.Error = err.Error()
+	} else {
+		t.result.Succeeded = append(t.result.Succeeded, id)
+		t.progress.Succeeded++
+	}
+
+	if err != nil || t.progress.Current%5 == 0 || t.progress.Current == t.progress.Total {
+		t.callback(t.progress)
+	}
+}
@@ -0,0 +1,154 @@
+package sticker
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"mymeme/memeFile/i18n"
+)
+
+// sourceDirName 保存原始贴纸文件的隐藏子目录名
+const sourceDirName = ".source"
+
+// SetKeepSource 设置是否在输出目录的 .source 子目录中保留原始 tgs/webm/webp 文件
+func (td *TelegramDownloader) SetKeepSource(keep bool) {
+	td.keepSource = keep
+}
+
+// sourceExt 根据贴纸类型返回原始文件扩展名
+func sourceExt(sticker TelegramSticker) string {
+	if sticker.IsVideo {
+		return ".webm"
+	} else if sticker.IsAnimated {
+		return ".tgs"
+	}
+	return ".webp"
+}
+
+// saveSource 将原始文件写入 saveDir/.source/fileName
+func (td *TelegramDownloader) saveSource(data []byte, saveDir string, fileName string) error {
+	sourceDir := filepath.Join(saveDir, sourceDirName)
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		return i18n.Wrap(err, i18n.ErrCreateSourceDir)
+	}
+
+	return os.WriteFile(filepath.Join(sourceDir, fileName), data, 0644)
+}
+
+// ReconvertStickerSet 使用 .source 中保留的原始文件重新生成 GIF/PNG，无需重新下载
+// 输出写回清单中记录的当前文件名（文件可能已被重命名），输出文件已被删除或移走的贴纸记为跳过
+func (td *TelegramDownloader) ReconvertStickerSet(folderPath string, progressCallback func(DownloadProgress)) (*DownloadResult, error) {
+	sourceDir := filepath.Join(folderPath, sourceDirName)
+
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, i18n.Errorf(i18n.ErrSourceDirNotFound, sourceDir)
+		}
+		return nil, i18n.Wrap(err, i18n.ErrReadSourceDir)
+	}
+
+	var sources []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".tgs", ".webm", ".webp":
+			sources = append(sources, entry.Name())
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, i18n.Errorf(i18n.ErrSourceDirEmpty, sourceDir)
+	}
+
+	result := newDownloadResult(folderPath)
+
+	// 自定义表情集合使用单独的尺寸配置
+	converter := td
+	manifest, err := ReadManifest(folderPath)
+	if err == nil {
+		result.Name = manifest.Name
+		result.Title = manifest.Title
+		if manifest.StickerType == StickerTypeCustomEmoji {
+			converter = td.withProfile(td.profile.emojiProfile())
+		}
+	}
+
+	// 源文件名 -> 当前输出文件名
+	outputs := make(map[string]string, len(sources))
+	var pending []string
+	for _, name := range sources {
+		id := strings.TrimSuffix(name, filepath.Ext(name))
+		output := ""
+		if manifest != nil {
+			output = manifest.Outputs[id]
+		}
+		if output == "" {
+			output = outputFileName(folderPath, id)
+		}
+		if _, err := os.Stat(filepath.Join(folderPath, output)); output == "" || err != nil {
+			log.Printf("贴纸的输出文件不存在，跳过重新转换: %s", id)
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		outputs[name] = output
+		pending = append(pending, name)
+	}
+	result.Total = len(pending)
+
+	tracker := newProgressTracker(result, progressCallback)
+	tracker.phase(PhaseConverting)
+
+	semaphore := make(chan struct{}, 3)
+	var wg sync.WaitGroup
+	var renamedMu sync.Mutex
+	renamed := make(map[string]string)
+
+	for _, name := range pending {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			ext := strings.ToLower(filepath.Ext(name))
+			id := strings.TrimSuffix(name, filepath.Ext(name))
+			output := outputs[name]
+			outputBase := strings.TrimSuffix(output, filepath.Ext(output))
+
+			data, err := os.ReadFile(filepath.Join(sourceDir, name))
+			if err == nil {
+				err = converter.convertSource(data, ext, folderPath, outputBase, 0, 0)
+			}
+			if err != nil {
+				log.Printf("重新转换贴纸失败 %s: %v", name, err)
+			} else if newOutput := converter.outputName(ext, outputBase); newOutput != output {
+				// 输出格式改变时扩展名随之改变
+				renamedMu.Lock()
+				renamed[output] = newOutput
+				renamedMu.Unlock()
+			}
+			tracker.done(id, err)
+		}(name)
+	}
+
+	wg.Wait()
+
+	if len(renamed) > 0 {
+		if err := RenameManifestOutputs(folderPath, renamed); err != nil {
+			log.Printf("更新贴纸清单失败: %v", err)
+		}
+	}
+
+	log.Printf("重新转换完成: 共 %d 个，失败 %d 个", result.Total, len(result.Failed))
+
+	err = result.finish()
+	tracker.phase(PhaseDone)
+	return result, err
+}
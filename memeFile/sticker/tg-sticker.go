@@ -18,33 +18,64 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	rlottie "github.com/yazmeyaa/go-rlottie"
+
+	"mymeme/memeFile/i18n"
 )
 
-type TelegramSticker struct {
+// 贴纸集合类型
+const (
+	StickerTypeRegular     = "regular"
+	StickerTypeMask        = "mask"
+	StickerTypeCustomEmoji = "custom_emoji"
+)
+
+type TelegramPhotoSize struct {
 	FileID       string `json:"file_id"`
 	FileUniqueID string `json:"file_unique_id"`
 	Width        int    `json:"width"`
 	Height       int    `json:"height"`
-	IsAnimated   bool   `json:"is_animated"`
-	IsVideo      bool   `json:"is_video"`
+}
+
+type TelegramSticker struct {
+	FileID        string             `json:"file_id"`
+	FileUniqueID  string             `json:"file_unique_id"`
+	Type          string             `json:"type"`
+	Width         int                `json:"width"`
+	Height        int                `json:"height"`
+	IsAnimated    bool               `json:"is_animated"`
+	IsVideo       bool               `json:"is_video"`
+	Emoji         string             `json:"emoji"`
+	CustomEmojiID string             `json:"custom_emoji_id"`
+	SetName       string             `json:"set_name"`
+	Thumbnail     *TelegramPhotoSize `json:"thumbnail"`
+}
+
+type TelegramStickerSet struct {
+	Name        string             `json:"name"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	StickerType string             `json:"sticker_type"`
+	IsAnimated  bool               `json:"is_animated"`
+	IsVideo     bool               `json:"is_video"`
+	Stickers    []TelegramSticker  `json:"stickers"`
+	Thumbnail   *TelegramPhotoSize `json:"thumbnail"`
 }
 
 type TelegramAPIResponse struct {
-	OK     bool `json:"ok"`
-	Result struct {
-		Name        string            `json:"name"`
-		Title       string            `json:"title"`
-		Description string            `json:"description"`
-		IsAnimated  bool              `json:"is_animated"`
-		IsVideo     bool              `json:"is_video"`
-		Stickers    []TelegramSticker `json:"stickers"`
-	} `json:"result"`
-	Description string `json:"description"`
+	OK          bool               `json:"ok"`
+	Result      TelegramStickerSet `json:"result"`
+	Description string             `json:"description"`
+}
+
+type TelegramCustomEmojiResponse struct {
+	OK          bool              `json:"ok"`
+	Result      []TelegramSticker `json:"result"`
+	Description string            `json:"description"`
 }
 
 type TelegramFileResponse struct {
@@ -55,35 +86,23 @@ type TelegramFileResponse struct {
 	} `json:"result"`
 }
 
-type DownloadProgress struct {
-	Current int    `json:"current"`
-	Total   int    `json:"total"`
-	Status  string `json:"status"`
-}
-
 type TelegramDownloader struct {
 	ctx        context.Context
 	botToken   string
 	proxyURL   string
+	proxy      *url.URL // 实际使用的代理，nil 表示直连
 	client     *http.Client
 	ffmpegPath string
+	keepSource bool              // 是否保留原始 tgs/webm/webp 文件
+	profile    ConversionProfile // 转换配置
 }
 
-func NewTelegramDownloader(ctx context.Context, botToken, proxyURL string, needProxy bool) *TelegramDownloader {
-	var transport *http.Transport
-
-	if needProxy && proxyURL != "" {
-		parsedProxyURL, err := url.Parse(proxyURL)
-		if err == nil {
-			transport = &http.Transport{
-				Proxy: http.ProxyURL(parsedProxyURL),
-			}
-		}
-	}
-
-	if transport == nil {
-		// 不设置代理
-		transport = &http.Transport{}
+// NewTelegramDownloader 创建下载器，needProxy 为 true 时使用 proxyURL 指定的代理
+// proxyURL 为空或为 auto 时使用环境变量或系统代理，代理地址无效时返回错误
+func NewTelegramDownloader(ctx context.Context, botToken, proxyURL string, needProxy bool) (*TelegramDownloader, error) {
+	transport, proxy, err := newProxyTransport(proxyURL, needProxy)
+	if err != nil {
+		return nil, err
 	}
 
 	client := &http.Client{
@@ -95,67 +114,99 @@ func NewTelegramDownloader(ctx context.Context, botToken, proxyURL string, needP
 		ctx:        ctx,
 		botToken:   botToken,
 		proxyURL:   proxyURL,
+		proxy:      proxy,
 		client:     client,
 		ffmpegPath: "ffmpeg",
-	}
+		profile:    DefaultProfile,
+	}, nil
 }
 
-func (td *TelegramDownloader) DownloadTgStickerSet(stickerSetName string, savePath string, progressCallback func(DownloadProgress)) error {
-	// 获取贴纸集
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getStickerSet?name=%s", td.botToken, stickerSetName)
-	resp, err := td.client.Get(apiURL)
+// SetProfile 设置贴纸转换配置
+func (td *TelegramDownloader) SetProfile(profile ConversionProfile) {
+	td.profile = profile.normalize()
+}
+
+// GetStickerSet 获取贴纸集合信息，stickerSetName 可以是集合名称或任意形式的贴纸链接
+func (td *TelegramDownloader) GetStickerSet(stickerSetName string) (*TelegramStickerSet, error) {
+	ref, err := ParseStickerSetRef(stickerSetName)
 	if err != nil {
-		return fmt.Errorf("获取贴纸集合失败: %v", err)
+		return nil, err
+	}
+
+	query := url.Values{"name": {ref.Name}}
+	apiURL := fmt.Sprintf("%s/bot%s/getStickerSet?%s", telegramAPIBase, td.botToken, query.Encode())
+	resp, err := td.get(apiURL)
+	if err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrFetchStickerSet)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, i18n.Errorf(i18n.ErrHTTPStatus, resp.StatusCode, resp.Status)
 	}
 
 	var apiResp TelegramAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return fmt.Errorf("解析响应失败: %v", err)
+		return nil, i18n.Wrap(err, i18n.ErrParseResponse)
 	}
 
 	if !apiResp.OK {
-		return fmt.Errorf("API 错误: %s", apiResp.Description)
+		return nil, i18n.Errorf(i18n.ErrAPI, td.redact(apiResp.Description))
 	}
 
-	if err := os.MkdirAll(savePath, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %v", err)
+	set := apiResp.Result
+	if set.StickerType == StickerTypeCustomEmoji {
+		for i := range set.Stickers {
+			set.Stickers[i].Type = StickerTypeCustomEmoji
+		}
 	}
+	return &set, nil
+}
 
-	stickers := apiResp.Result.Stickers
+// DownloadTgStickerSet 下载整个贴纸集合到 savePath，返回每个贴纸的处理结果
+// 全部贴纸都失败时同时返回错误
+func (td *TelegramDownloader) DownloadTgStickerSet(stickerSetName string, savePath string, progressCallback func(DownloadProgress)) (*DownloadResult, error) {
+	result := newDownloadResult(savePath)
+	tracker := newProgressTracker(result, progressCallback)
 
-	total := len(stickers)
+	// 获取贴纸集
+	tracker.phase(PhaseFetching)
+	set, err := td.GetStickerSet(stickerSetName)
+	if err != nil {
+		return nil, err
+	}
+	result.Name = set.Name
+	result.Title = set.Title
+	result.Total = len(set.Stickers)
 
-	// 更新进度
-	progressCallback(DownloadProgress{
-		Current: 0,
-		Total:   total,
-		Status:  fmt.Sprintf("开始下载 %s (%d 个贴纸)", apiResp.Result.Title, total),
-	})
+	if err := os.MkdirAll(savePath, 0755); err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrCreateDir)
+	}
+
+	tracker.phase(PhaseDownloading)
+	td.downloadStickers(set.Stickers, savePath, tracker)
 
-	var successCount int64
-	var failedCount int64
-	var completedCount int64
+	log.Printf("下载完成: 成功 %d 个，失败 %d 个", len(result.Succeeded), len(result.Failed))
 
-	maxConcurrency := 6 // 默认并发数
-	if len(stickers) > 0 && stickers[0].IsAnimated {
-		maxConcurrency = 3 // TGS
+	tracker.phase(PhaseWriting)
+	if err := updateManifest(savePath, set, result.Succeeded); err != nil {
+		log.Printf("更新贴纸清单失败: %v", err)
 	}
 
+	err = result.finish()
+	tracker.phase(PhaseDone)
+	return result, err
+}
+
+// downloadStickers 并发下载并转换贴纸，结果记录到 tracker
+func (td *TelegramDownloader) downloadStickers(stickers []TelegramSticker, savePath string, tracker *progressTracker) {
+	total := len(stickers)
+
+	// TGS 转换的内存占用由全局渲染预算控制，这里只限制网络并发
+	maxConcurrency := 6
+
 	semaphore := make(chan struct{}, maxConcurrency)
 	var wg sync.WaitGroup
-	var progressMutex sync.Mutex
-
-	// 初始化进度显示
-	progressCallback(DownloadProgress{
-		Current: 0,
-		Total:   total,
-		Status:  fmt.Sprintf("开始下载 %d 个贴纸", total),
-	})
 
 	for i, sticker := range stickers {
 		wg.Add(1)
@@ -165,150 +216,213 @@ func (td *TelegramDownloader) DownloadTgStickerSet(stickerSetName string, savePa
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			if err := td.downloadSticker(sticker, savePath, index+1, total); err != nil {
+			err := td.downloadSticker(sticker, savePath, index+1, total, tracker)
+			if err != nil {
 				log.Printf("下载贴纸失败 %s: %v", sticker.FileID, err)
-				atomic.AddInt64(&failedCount, 1)
-			} else {
-				atomic.AddInt64(&successCount, 1)
 			}
+			tracker.done(sticker.FileUniqueID, err)
+		}(sticker, i)
+	}
 
-			currentCompleted := atomic.AddInt64(&completedCount, 1)
+	wg.Wait()
+}
 
-			progressMutex.Lock()
-			currentSuccess := atomic.LoadInt64(&successCount)
-			currentFailed := atomic.LoadInt64(&failedCount)
+// downloadSticker 下载并转换单个贴纸，下载步骤结束后通知 tracker，转换结果由调用方记录
+func (td *TelegramDownloader) downloadSticker(sticker TelegramSticker, saveDir string, current, total int, tracker *progressTracker) error {
+	log.Printf("开始下载第 %d/%d 张贴纸，ID: %s", current, total, sticker.FileID)
 
-			// 5下一更新 或 最后一个文件时更新
-			if currentCompleted%5 == 0 || currentCompleted == int64(total) {
-				progressCallback(DownloadProgress{
-					Current: int(currentCompleted),
-					Total:   total,
-					Status:  fmt.Sprintf("下载完成 %d/%d (成功: %d, 失败: %d)", currentCompleted, total, currentSuccess, currentFailed),
-				})
-			}
-			progressMutex.Unlock()
-		}(sticker, i)
+	fileData, _, err := td.fetchFile(sticker.FileID)
+	tracker.downloaded()
+	if err != nil {
+		return err
 	}
 
-	wg.Wait()
+	fileName := sticker.FileUniqueID
 
-	log.Printf("下载完成: 成功 %d 个，失败 %d 个", atomic.LoadInt64(&successCount), atomic.LoadInt64(&failedCount))
+	log.Printf("文件类型: %s, 尺寸: %dx%d",
+		func() string {
+			if sticker.IsVideo {
+				return "视频贴纸"
+			} else if sticker.IsAnimated {
+				return "TGS动画贴纸"
+			} else {
+				return "静态贴纸"
+			}
+		}(), sticker.Width, sticker.Height)
 
-	stickerData := map[string]interface{}{
-		"title": apiResp.Result.Title,
-		"name":  apiResp.Result.Name,
-		"icon":  nil,
-		"url":   fmt.Sprintf("https://t.me/addstickers/%s", stickerSetName),
+	if td.keepSource {
+		if err := td.saveSource(fileData, saveDir, fileName+sourceExt(sticker)); err != nil {
+			// 源文件保存失败不影响转换结果
+			log.Printf("保存源文件失败 %s: %v", fileName, err)
+		}
 	}
 
-	stickerDataPath := filepath.Join(savePath, "sticker.json")
-	data, _ := json.MarshalIndent(stickerData, "", "  ")
-	os.WriteFile(stickerDataPath, data, 0644)
+	// 自定义表情使用单独的尺寸配置
+	converter := td
+	if sticker.Type == StickerTypeCustomEmoji {
+		converter = td.withProfile(td.profile.emojiProfile())
+	}
 
-	progressCallback(DownloadProgress{
-		Current: total,
-		Total:   total,
-		Status:  fmt.Sprintf("下载完成: %s (成功: %d, 失败: %d)", apiResp.Result.Title, atomic.LoadInt64(&successCount), atomic.LoadInt64(&failedCount)),
-	})
+	width, height := 0, 0
+	if sticker.IsAnimated {
+		width, height = converter.profile.animatedSize(sticker.Width, sticker.Height)
+	}
 
-	return nil
+	return converter.convertSource(fileData, sourceExt(sticker), saveDir, fileName, width, height)
 }
 
-func (td *TelegramDownloader) downloadSticker(sticker TelegramSticker, saveDir string, current, total int) error {
-	log.Printf("开始下载第 %d/%d 张贴纸，ID: %s", current, total, sticker.FileID)
-
-	fileURL := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", td.botToken, sticker.FileID)
-	resp, err := td.client.Get(fileURL)
+// fetchFile 通过 getFile 获取文件路径并下载文件内容，返回文件内容和服务器上的文件路径
+func (td *TelegramDownloader) fetchFile(fileID string) ([]byte, string, error) {
+	query := url.Values{"file_id": {fileID}}
+	fileURL := fmt.Sprintf("%s/bot%s/getFile?%s", telegramAPIBase, td.botToken, query.Encode())
+	resp, err := td.get(fileURL)
 	if err != nil {
-		return fmt.Errorf("获取文件信息失败: %v", err)
+		return nil, "", i18n.Wrap(err, i18n.ErrGetFileInfo)
 	}
 	defer resp.Body.Close()
 
 	var fileResp TelegramFileResponse
 	if err := json.NewDecoder(resp.Body).Decode(&fileResp); err != nil {
-		return fmt.Errorf("解析文件信息失败: %v", err)
+		return nil, "", i18n.Wrap(err, i18n.ErrParseFileInfo)
 	}
 
 	if !fileResp.OK {
-		return fmt.Errorf("获取文件信息失败")
+		return nil, "", i18n.Errorf(i18n.ErrGetFileInfo)
 	}
 
-	downloadURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", td.botToken, fileResp.Result.FilePath)
-	log.Printf("下载链接: %s", downloadURL)
-	fileResp2, err := td.client.Get(downloadURL)
+	downloadURL := fmt.Sprintf("%s/file/bot%s/%s", telegramAPIBase, td.botToken, fileResp.Result.FilePath)
+	log.Printf("下载文件: %s", fileResp.Result.FilePath)
+	fileResp2, err := td.get(downloadURL)
 	if err != nil {
-		return fmt.Errorf("下载文件失败: %v", err)
+		return nil, "", i18n.Wrap(err, i18n.ErrDownloadFile)
 	}
 	defer fileResp2.Body.Close()
 
 	fileData, err := io.ReadAll(fileResp2.Body)
 	if err != nil {
-		return fmt.Errorf("读取文件内容失败: %v", err)
+		return nil, "", i18n.Wrap(err, i18n.ErrReadFileContent)
 	}
 
-	fileName := sticker.FileUniqueID
+	return fileData, fileResp.Result.FilePath, nil
+}
 
-	log.Printf("文件类型: %s, 尺寸: %dx%d",
-		func() string {
-			if sticker.IsVideo {
-				return "视频贴纸"
-			} else if sticker.IsAnimated {
-				return "TGS动画贴纸"
-			} else {
-				return "静态贴纸"
-			}
-		}(), sticker.Width, sticker.Height)
+// outputName 获取按源文件类型转换后的输出文件名：动图按配置的格式，静态贴纸为 PNG
+func (td *TelegramDownloader) outputName(ext string, baseName string) string {
+	if ext == ".webp" {
+		return baseName + ".png"
+	}
+	return baseName + td.profile.animatedExt()
+}
 
-	if sticker.IsVideo {
-		fileName += ".gif"
-		filePath := filepath.Join(saveDir, fileName)
-		return td.convertWebmToGif(fileData, filePath)
-	} else if sticker.IsAnimated {
-		fileName += ".gif"
-		filePath := filepath.Join(saveDir, fileName)
-		width := sticker.Width / 2
-		height := sticker.Height / 2
-		if width == 0 {
-			width = 256
-		}
-		if height == 0 {
-			height = 256
+// convertSource 按源文件类型转换贴纸，动图按配置输出为 GIF/APNG/WebP，静态贴纸输出为 PNG
+func (td *TelegramDownloader) convertSource(data []byte, ext string, saveDir string, baseName string, width, height int) error {
+	outputPath := filepath.Join(saveDir, td.outputName(ext, baseName))
+	var err error
+	switch ext {
+	case ".webm":
+		err = td.convertWebmToAnimation(data, outputPath)
+	case ".tgs":
+		err = td.convertTGSToAnimation(data, outputPath, width, height)
+	case ".webp":
+		return td.convertWebpToPng(data, outputPath)
+	default:
+		return i18n.Errorf(i18n.ErrUnsupportedSource, ext)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 确认新文件已写入后再删除旧格式的动图，转换失败时保留原有文件
+	if info, err := os.Stat(outputPath); err != nil || info.Size() == 0 {
+		return i18n.Errorf(i18n.ErrCreateOutput, outputPath)
+	}
+
+	// 切换输出格式后删除旧格式的动图，避免同一贴纸出现多份
+	for _, stale := range []string{".gif", ".png", ".webp"} {
+		if stale != td.profile.animatedExt() {
+			os.Remove(filepath.Join(saveDir, baseName+stale))
 		}
-		return td.convertTGSToGif(fileData, filePath, width, height)
-	} else {
-		fileName += ".png"
-		filePath := filepath.Join(saveDir, fileName)
-		return td.convertWebpToPng(fileData, filePath)
 	}
+	return nil
 }
 
 func (td *TelegramDownloader) convertWebpToPng(data []byte, outputPath string) error {
-	cmd := exec.Command(td.ffmpegPath,
-		"-i", "pipe:0",
-		"-vf", "scale=512:-1:flags=lanczos",
+	args := []string{"-i", "pipe:0"}
+	if td.profile.StaticSize > 0 {
+		args = append(args, "-vf", fmt.Sprintf("scale=%d:-1:flags=lanczos", td.profile.StaticSize))
+	}
+	args = append(args,
 		"-c:v", "png",
 		"-pix_fmt", "rgba",
 		"-y", outputPath)
 
+	cmd := exec.Command(td.ffmpegPath, args...)
+
 	cmd.Stdin = bytes.NewReader(data)
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("WebP 转 PNG 失败: %v, 错误: %s", err, stderr.String())
+		return i18n.Errorf(i18n.ErrConvertWebP, err, stderr.String())
 	}
 
 	return nil
 }
 
-func (td *TelegramDownloader) convertWebmToGif(data []byte, outputPath string) error {
-	cmd := exec.Command(td.ffmpegPath,
-		"-vcodec", "libvpx-vp9",
-		"-i", "pipe:0",
-		"-vf", "split[s0][s1];[s0]palettegen[p];[s1][p]paletteuse",
-		"-loop", "0",
-		"-y", outputPath)
+func (td *TelegramDownloader) convertWebmToAnimation(data []byte, outputPath string) error {
+	profile := td.profile
+
+	var filters []string
+	if profile.MaxSize > 0 {
+		filters = append(filters, fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease:flags=lanczos", profile.MaxSize, profile.MaxSize))
+	}
+	if profile.FPS > 0 {
+		filters = append(filters, fmt.Sprintf("fps=%g", profile.FPS))
+	}
+
+	dither := "sierra2_4a"
+	if !profile.Dither {
+		dither = "none"
+	}
+	paletteGen := fmt.Sprintf("palettegen=max_colors=%d", profile.Colors)
+	paletteUse := fmt.Sprintf("paletteuse=dither=%s:alpha_threshold=%d", dither, profile.AlphaThreshold)
+	if profile.PaletteScope == PaletteScopeFrame {
+		// 每帧单独生成调色板
+		paletteGen += ":stats_mode=single"
+		paletteUse += ":new=1"
+	}
+
+	// FrameCap 只用于 TGS 渲染，截断帧数会让 WebM 贴纸只播放开头一段
+	args := []string{"-vcodec", "libvpx-vp9", "-i", "pipe:0"}
+
+	switch profile.Format {
+	case FormatAPNG:
+		if len(filters) > 0 {
+			args = append(args, "-vf", strings.Join(filters, ","))
+		}
+		args = append(args,
+			"-pix_fmt", "rgba",
+			"-f", "apng",
+			"-plays", fmt.Sprint(max(profile.Loop, 0)))
+	case FormatWebP:
+		if len(filters) > 0 {
+			args = append(args, "-vf", strings.Join(filters, ","))
+		}
+		args = append(args,
+			"-c:v", "libwebp",
+			"-pix_fmt", "yuva420p",
+			"-quality", "90",
+			"-loop", fmt.Sprint(max(profile.Loop, 0)))
+	default:
+		filters = append(filters, fmt.Sprintf("split[s0][s1];[s0]%s[p];[s1][p]%s", paletteGen, paletteUse))
+		args = append(args,
+			"-vf", strings.Join(filters, ","),
+			"-loop", fmt.Sprint(profile.gifLoopCount()))
+	}
+	args = append(args, "-y", outputPath)
+
+	cmd := exec.Command(td.ffmpegPath, args...)
 
 	cmd.Stdin = bytes.NewReader(data)
 
@@ -316,143 +430,127 @@ func (td *TelegramDownloader) convertWebmToGif(data []byte, outputPath string) e
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		log.Printf("WebM 转 GIF 失败: %v, 错误: %s", err, stderr.String())
+		return i18n.Errorf(i18n.ErrConvertWebM, err, stderr.String())
 	}
 
 	return nil
 }
 
-func (td *TelegramDownloader) convertTGSToGif(data []byte, outputPath string, width, height int) error {
+func (td *TelegramDownloader) convertTGSToAnimation(data []byte, outputPath string, width, height int) error {
 	// 解压 TGS 文件
 	gz, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("无法创建 GZIP 读取器: %w", err)
+		return i18n.Wrap(err, i18n.ErrGzipReader)
 	}
 	defer gz.Close()
 
 	jsonData, err := io.ReadAll(gz)
 	if err != nil {
-		return fmt.Errorf("无法读取解压后的 JSON 数据: %w", err)
+		return i18n.Wrap(err, i18n.ErrReadLottie)
 	}
 
 	// 清除rlottie缓存
-	rlottie.LottieConfigureModelCacheSize(0) // 禁用缓存
+	disableLottieCache.Do(func() {
+		rlottie.LottieConfigureModelCacheSize(0) // 禁用缓存
+	})
 
 	// 使用 rlottie 加载动画，使用唯一key避免缓存
 	uniqueKey := fmt.Sprintf("tgs_%s", outputPath)
 	anim := rlottie.LottieAnimationFromData(string(jsonData), uniqueKey, "")
 	if anim == nil {
-		return fmt.Errorf("无法从 JSON 数据加载 Lottie 动画")
+		return i18n.Errorf(i18n.ErrLoadLottie)
 	}
 	defer rlottie.LottieAnimationDestroy(anim)
 
+	profile := td.profile
+
+	// 未指定尺寸时（如从源文件重新转换）按原始尺寸和配置计算
+	if width <= 0 || height <= 0 {
+		w, h := rlottie.LottieAnimationGetSize(anim)
+		width, height = profile.animatedSize(int(w), int(h))
+	}
+
 	duration := rlottie.LottieAnimationGetDuration(anim)
 	frameRate := rlottie.LottieAnimationGetFramerate(anim)
 	originalTotalFrames := int(rlottie.LottieAnimationGetTotalframe(anim))
 
 	if originalTotalFrames == 0 {
-		return fmt.Errorf("错误: 动画不包含任何帧")
+		return i18n.Errorf(i18n.ErrNoFrames)
 	}
 
-	// 计算帧数
-	var totalFrames int
-	var delay int
-
-	if frameRate > 0 {
-		// 如果原始帧率很高，适当降低但保持流畅
-		if frameRate >= 30 {
-			totalFrames = 24 // 高帧率动画用24帧
-		} else if frameRate >= 15 {
-			totalFrames = 20 // 中等帧率用20帧
-		} else {
-			totalFrames = 16 // 低帧率用16帧
-		}
-	} else {
-		// 如果无法获取原始帧率，根据时长判断
-		if duration <= 2.0 {
-			totalFrames = 24
-		} else if duration <= 5.0 {
-			totalFrames = 20
-		} else {
-			totalFrames = 16
+	// 计算需要渲染的帧及延迟
+	plan := profile.planFrames(frameRate, duration, originalTotalFrames)
+	totalFrames := len(plan.frames)
+
+	log.Printf("TGS 动画信息: 时长 %.2f 秒, 原始帧率 %.2f, 原始帧数 %d, 输出帧数 %d, 输出时长 %d/100秒",
+		duration, frameRate, originalTotalFrames, totalFrames, plan.totalDelay())
+
+	// 渲染帧和编码期间占用的内存计入全局预算，预算不足时等待其他转换完成
+	workers := renderWorkerCount(totalFrames)
+	budget := int64(totalFrames+workers) * int64(width) * int64(height) * 4
+	budget = renderBudget.acquire(budget)
+	defer renderBudget.release(budget)
+
+	frames := td.renderTGSFrames(string(jsonData), uniqueKey, anim, plan, width, height, workers)
+	defer releaseFrames(frames)
+
+	switch profile.Format {
+	case FormatAPNG:
+		err = td.encodeAPNG(frames, plan, outputPath)
+	case FormatWebP:
+		err = td.encodeWebP(frames, plan, outputPath)
+	default:
+		if profile.Quantizer == QuantizerMedianCut {
+			// 自适应调色板前先处理半透明边缘
+			matte, _ := parseMatte(profile.Matte)
+			for _, frame := range frames {
+				flattenAlpha(frame, uint8(profile.AlphaThreshold), matte)
+			}
 		}
+		err = td.encodeGIF(frames, plan, outputPath)
 	}
-
-	// 计算延迟
-	if duration > 0 {
-		// 根据动画总时长和帧数计算每帧延迟
-		delayPerFrame := duration / float64(totalFrames) // 每帧的秒数
-		delay = int(delayPerFrame * 100)                 // 转换为百分之一秒
-
-		// 限制延迟范围，避免过快或过慢
-		if delay < 2 {
-			delay = 2 // 最小2/100秒 = 20ms
-		} else if delay > 20 {
-			delay = 20 // 最大20/100秒 = 200ms
-		}
-	} else {
-		delay = 4 // 默认4/100秒 = 40ms
+	if err != nil {
+		return err
 	}
 
-	log.Printf("TGS 动画信息: 时长 %.2f 秒, 原始帧率 %.2f, 原始帧数 %d, 输出帧数 %d, 延迟 %d/100秒",
-		duration, frameRate, originalTotalFrames, totalFrames, delay)
+	log.Printf("TGS 转换成功 (%s): %s", profile.Format, outputPath)
+	return nil
+}
+
+// encodeGIF 将 RGBA 帧按配置量化后编码为 GIF
+func (td *TelegramDownloader) encodeGIF(frames []*image.RGBA, plan framePlan, outputPath string) error {
+	profile := td.profile
+	totalFrames := len(frames)
 
 	// 预分配所有切片
 	outGif := &gif.GIF{
-		Image:    make([]*image.Paletted, 0, totalFrames),
-		Delay:    make([]int, 0, totalFrames),
-		Disposal: make([]byte, totalFrames),
+		Image:     make([]*image.Paletted, 0, totalFrames),
+		Delay:     make([]int, 0, totalFrames),
+		Disposal:  make([]byte, totalFrames),
+		LoopCount: profile.gifLoopCount(),
 	}
 
-	widthUint := uint(width)
-	heightUint := uint(height)
-
-	for i := 0; i < totalFrames; i++ {
-		progress := float64(i) / float64(totalFrames-1) // 0 到 1 的进度
-		originalFrameNum := uint(progress * float64(originalTotalFrames-1))
-
-		buffer := make([]byte, widthUint*heightUint*4)
-		rgbaBuffer := make([]byte, widthUint*heightUint*4)
-
-		rlottie.LottieAnimationRender(anim, originalFrameNum, buffer, widthUint, heightUint, widthUint*4)
-
-		for j := 0; j < len(buffer); j += 4 {
-			// 交换 R 和 B 通道：BGRA -> RGBA
-			rgbaBuffer[j] = buffer[j+2]   // R = B
-			rgbaBuffer[j+1] = buffer[j+1] // G = G
-			rgbaBuffer[j+2] = buffer[j]   // B = R
-			rgbaBuffer[j+3] = buffer[j+3] // A = A
-		}
-
-		rgbaFrame := &image.RGBA{
-			Pix:    rgbaBuffer,
-			Stride: int(widthUint * 4),
-			Rect:   image.Rect(0, 0, int(widthUint), int(heightUint)),
-		}
+	palettes := profile.buildPalettes(frames)
 
+	for i, frame := range frames {
 		// 将 RGBA 帧转换为支持透明的 Paletted 帧
-		palettedFrame := td.rgbaToPaletted(rgbaFrame)
+		palettedFrame := td.rgbaToPaletted(frame, palettes[i], profile.Dither, uint8(profile.AlphaThreshold))
 
 		outGif.Image = append(outGif.Image, palettedFrame)
-		outGif.Delay = append(outGif.Delay, delay)
+		outGif.Delay = append(outGif.Delay, plan.delays[i])
 		outGif.Disposal[i] = gif.DisposalBackground
-
-		if (i+1)%10 == 0 || i == totalFrames-1 {
-			log.Printf("TGS 转换进度: %d / %d 帧...", i+1, totalFrames)
-		}
 	}
 
 	f, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("无法创建输出文件 '%s': %w", outputPath, err)
+		return i18n.Wrap(err, i18n.ErrCreateOutput, outputPath)
 	}
 	defer f.Close()
 
 	if err := gif.EncodeAll(f, outGif); err != nil {
-		return fmt.Errorf("编码 GIF 失败: %w", err)
+		return i18n.Wrap(err, i18n.ErrEncodeGIF)
 	}
 
-	log.Printf("TGS 转 GIF 成功: %s", outputPath)
 	return nil
 }
 
@@ -463,13 +561,17 @@ var globalPalette = func() color.Palette {
 	return p
 }()
 
-// rgbaToPaletted 将 RGBA 图像转换为 Paletted 图像，保留透明度并使用抖动算法保证颜色质量
-func (td *TelegramDownloader) rgbaToPaletted(rgba *image.RGBA) *image.Paletted {
+// rgbaToPaletted 将 RGBA 图像转换为 Paletted 图像，alpha 低于 alphaThreshold 的像素设为透明，可选使用抖动算法保证颜色质量
+func (td *TelegramDownloader) rgbaToPaletted(rgba *image.RGBA, pal color.Palette, dither bool, alphaThreshold uint8) *image.Paletted {
 	bounds := rgba.Bounds()
-	paletted := image.NewPaletted(bounds, globalPalette)
+	paletted := image.NewPaletted(bounds, pal)
 
-	// 使用 Floyd-Steinberg 抖动算法进行绘制，以保证颜色质量
-	draw.FloydSteinberg.Draw(paletted, bounds, rgba, image.Point{})
+	if dither {
+		// 使用 Floyd-Steinberg 抖动算法进行绘制，以保证颜色质量
+		draw.FloydSteinberg.Draw(paletted, bounds, rgba, image.Point{})
+	} else {
+		draw.Draw(paletted, bounds, rgba, image.Point{}, draw.Src)
+	}
 
 	// 优化透明区域处理：直接操作像素数据而不是逐个像素访问
 	pix := rgba.Pix
@@ -479,7 +581,7 @@ func (td *TelegramDownloader) rgbaToPaletted(rgba *image.RGBA) *image.Paletted {
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 			idx := y*stride + x*4
-			if pix[idx+3] < 128 { // Alpha < 128
+			if pix[idx+3] < alphaThreshold {
 				palettedIdx := y*paletted.Stride + x
 				palettedPix[palettedIdx] = 0 // 设置为透明色索引
 			}
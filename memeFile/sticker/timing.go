@@ -0,0 +1,119 @@
+package sticker
+
+import "math"
+
+// framePlan TGS 输出帧计划
+type framePlan struct {
+	frames []uint // 每个输出帧对应的源帧号
+	delays []int  // 每个输出帧的延迟（1/100 秒）
+}
+
+// planFrames 根据配置计算需要渲染的源帧及其延迟
+func (p ConversionProfile) planFrames(frameRate, duration float64, originalTotalFrames int) framePlan {
+	if p.Accurate {
+		return p.planAccurateFrames(frameRate, duration, originalTotalFrames)
+	}
+	return p.planSampledFrames(frameRate, duration, originalTotalFrames)
+}
+
+// planSampledFrames 均匀抽取固定数量的帧，使用统一的延迟（早期版本的行为）
+func (p ConversionProfile) planSampledFrames(frameRate, duration float64, originalTotalFrames int) framePlan {
+	totalFrames := p.frameCount(frameRate, duration, originalTotalFrames)
+
+	// 计算延迟
+	var delay int
+	if duration > 0 {
+		// 根据动画总时长和帧数计算每帧延迟
+		delayPerFrame := duration / float64(totalFrames) // 每帧的秒数
+		delay = int(delayPerFrame * 100)                 // 转换为百分之一秒
+
+		// 限制延迟范围，避免过快或过慢
+		if delay < p.MinDelay {
+			delay = p.MinDelay
+		} else if delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	} else {
+		delay = 4 // 默认4/100秒 = 40ms
+	}
+
+	plan := framePlan{
+		frames: make([]uint, totalFrames),
+		delays: make([]int, totalFrames),
+	}
+	for i := 0; i < totalFrames; i++ {
+		progress := 0.0 // 0 到 1 的进度
+		if totalFrames > 1 {
+			progress = float64(i) / float64(totalFrames-1)
+		}
+		plan.frames[i] = uint(progress * float64(originalTotalFrames-1))
+		plan.delays[i] = delay
+	}
+	return plan
+}
+
+// planAccurateFrames 渲染所有源帧（或按目标帧率重采样），并保证总时长与原动画一致
+//
+// 每帧的起始时间按精确时间四舍五入到 1/100 秒后再求差，
+// 小数部分不会逐帧累积，长动画也不会出现时间漂移。
+// 间隔小于 MinDelay 的帧会被丢弃，其时长并入前一帧。
+func (p ConversionProfile) planAccurateFrames(frameRate, duration float64, originalTotalFrames int) framePlan {
+	if duration <= 0 {
+		if frameRate > 0 {
+			duration = float64(originalTotalFrames) / frameRate
+		} else {
+			duration = float64(originalTotalFrames) * 0.04 // 无法获取帧率时按 25fps 处理
+		}
+	}
+
+	outputFrames := originalTotalFrames
+	if p.FPS > 0 {
+		outputFrames = int(math.Round(duration * p.FPS))
+	}
+	if p.FrameCap > 0 && outputFrames > p.FrameCap {
+		outputFrames = p.FrameCap
+	}
+	if outputFrames < 1 {
+		outputFrames = 1
+	}
+
+	totalCentis := int(math.Round(duration * 100))
+	frameCentis := duration * 100 / float64(outputFrames)
+
+	var plan framePlan
+	var starts []int
+
+	for i := 0; i < outputFrames; i++ {
+		start := int(math.Round(float64(i) * frameCentis))
+		if len(starts) > 0 && (start-starts[len(starts)-1] < p.MinDelay || totalCentis-start < p.MinDelay) {
+			continue
+		}
+
+		sourceFrame := uint(float64(i) * float64(originalTotalFrames) / float64(outputFrames))
+		if int(sourceFrame) >= originalTotalFrames {
+			sourceFrame = uint(originalTotalFrames - 1)
+		}
+
+		plan.frames = append(plan.frames, sourceFrame)
+		starts = append(starts, start)
+	}
+
+	plan.delays = make([]int, len(starts))
+	for i := range starts {
+		end := totalCentis
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		plan.delays[i] = max(end-starts[i], p.MinDelay)
+	}
+	return plan
+}
+
+// totalDelay 计划的总时长（1/100 秒）
+func (fp framePlan) totalDelay() int {
+	total := 0
+	for _, d := range fp.delays {
+		total += d
+	}
+	return total
+}
@@ -0,0 +1,60 @@
+package sticker
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os/exec"
+
+	"mymeme/memeFile/i18n"
+)
+
+// encodeWebP 通过 ffmpeg 将 RGBA 帧编码为带 alpha 通道的动态 WebP
+// ffmpeg 的原始视频输入只支持固定帧率，这里按总时长计算平均帧率，保证整体播放时长不变
+func (td *TelegramDownloader) encodeWebP(frames []*image.RGBA, plan framePlan, outputPath string) error {
+	if len(frames) == 0 {
+		return i18n.Errorf(i18n.ErrNoFrames)
+	}
+
+	bounds := frames[0].Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	fps := 25.0
+	if total := plan.totalDelay(); total > 0 {
+		fps = float64(len(frames)) * 100 / float64(total)
+	}
+
+	// ffmpeg 的 rgba 像素格式为非预乘 alpha
+	var input bytes.Buffer
+	input.Grow(len(frames) * width * height * 4)
+	row := make([]byte, width*4)
+	for _, frame := range frames {
+		for y := 0; y < height; y++ {
+			unpremultiply(row, frame.Pix[y*frame.Stride:y*frame.Stride+width*4])
+			input.Write(row)
+		}
+	}
+
+	cmd := exec.Command(td.ffmpegPath,
+		"-f", "rawvideo",
+		"-pix_fmt", "rgba",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"-framerate", fmt.Sprintf("%.4f", fps),
+		"-i", "pipe:0",
+		"-c:v", "libwebp",
+		"-pix_fmt", "yuva420p",
+		"-quality", "90",
+		"-loop", fmt.Sprint(max(td.profile.Loop, 0)),
+		"-y", outputPath)
+
+	cmd.Stdin = &input
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return i18n.Errorf(i18n.ErrEncodeWebP, err, stderr.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package memeFile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+	"mymeme/memeFile/platform"
+	"mymeme/memeFile/sticker"
+)
+
+// 系统凭据存储中的服务名和账户名
+const (
+	keyringService = "QQmeme"
+	keyringAccount = "telegram-bot-token"
+)
+
+// 加密文件回退方案使用的文件名，保存在应用配置目录下
+const (
+	tokenFileName    = "bot_token.enc"
+	tokenKeyFileName = "secret.key"
+)
+
+// 凭据存储位置
+const (
+	TokenStorageNone    = "none"    // 未保存
+	TokenStorageKeyring = "keyring" // 系统凭据存储（Windows 凭据管理器 / MacOS 钥匙串）
+	TokenStorageFile    = "file"    // 加密文件
+)
+
+// botTokenFormat Bot Token 格式：<数字ID>:<密钥>
+var botTokenFormat = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]+$`)
+
+// BotTokenStatus 结构体 - Bot Token 的保存状态，不包含完整 Token
+type BotTokenStatus struct {
+	Saved   bool   `json:"saved"`   // 是否已保存
+	Storage string `json:"storage"` // 保存位置：none / keyring / file
+	Masked  string `json:"masked"`  // 隐藏后的 Token，用于界面显示
+}
+
+// tokenStore 优先使用系统凭据存储保存 Bot Token，不可用时回退为加密文件
+type tokenStore struct {
+	m       *MemeFile
+	keyring platform.Keyring
+}
+
+// newTokenStore 创建 Token 存储
+func newTokenStore(m *MemeFile) *tokenStore {
+	return &tokenStore{m: m, keyring: platform.NewKeyring()}
+}
+
+// get 读取已保存的 Token，依次尝试系统凭据存储和加密文件
+func (s *tokenStore) get() (string, string, error) {
+	if s.keyring != nil {
+		token, err := s.keyring.GetSecret(keyringService, keyringAccount)
+		if err != nil {
+			log.Printf("读取系统凭据失败，尝试加密文件: %v", err)
+		} else if token != "" {
+			return token, TokenStorageKeyring, nil
+		}
+	}
+
+	token, err := s.readFile()
+	if err != nil {
+		return "", TokenStorageNone, err
+	}
+	if token == "" {
+		return "", TokenStorageNone, nil
+	}
+	return token, TokenStorageFile, nil
+}
+
+// set 保存 Token，系统凭据存储写入成功后删除加密文件
+func (s *tokenStore) set(token string) (string, error) {
+	if s.keyring != nil {
+		err := s.keyring.SetSecret(keyringService, keyringAccount, token)
+		if err == nil {
+			if err := s.removeFile(); err != nil {
+				log.Printf("删除加密 Token 文件失败: %v", err)
+			}
+			return TokenStorageKeyring, nil
+		}
+		log.Printf("写入系统凭据失败，改用加密文件: %v", err)
+	}
+
+	if err := s.writeFile(token); err != nil {
+		return TokenStorageNone, err
+	}
+	return TokenStorageFile, nil
+}
+
+// clear 从所有存储位置删除 Token，其中一处删除失败时仍会尝试删除另一处
+func (s *tokenStore) clear() error {
+	var keyringErr error
+	if s.keyring != nil {
+		keyringErr = s.keyring.DeleteSecret(keyringService, keyringAccount)
+	}
+	return errors.Join(keyringErr, s.removeFile())
+}
+
+// filePath 获取应用配置目录下的文件路径
+func (s *tokenStore) filePath(name string) (string, error) {
+	dir, err := s.m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// cipher 创建加密文件使用的 AES-GCM，密钥由随机密钥文件和当前用户、主机信息派生
+// 复制到其他电脑或其他用户下的密钥文件无法解密 Token
+func (s *tokenStore) cipher() (cipher.AEAD, error) {
+	keyPath, err := s.filePath(tokenKeyFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := os.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		secret = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+			return nil, i18n.Wrap(err, i18n.ErrGenerateKey)
+		}
+		if err := os.WriteFile(keyPath, secret, 0600); err != nil {
+			return nil, i18n.Wrap(err, i18n.ErrSaveKey)
+		}
+	} else if err != nil {
+		return nil, i18n.Wrap(err, i18n.ErrReadKey)
+	}
+
+	hash := sha256.New()
+	hash.Write(secret)
+	if host, err := os.Hostname(); err == nil {
+		hash.Write([]byte(host))
+	}
+	if u, err := user.Current(); err == nil {
+		hash.Write([]byte(u.Uid))
+	}
+
+	block, err := aes.NewCipher(hash.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readFile 读取并解密加密文件中的 Token，文件不存在时返回空字符串
+func (s *tokenStore) readFile() (string, error) {
+	path, err := s.filePath(tokenFileName)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", i18n.Wrap(err, i18n.ErrReadTokenFile)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return "", i18n.Errorf(i18n.ErrTokenFileCorrupt)
+	}
+
+	aead, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", i18n.Errorf(i18n.ErrTokenFileCorrupt)
+	}
+
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", i18n.Errorf(i18n.ErrTokenDecrypt)
+	}
+	return string(plain), nil
+}
+
+// writeFile 加密 Token 并写入文件
+func (s *tokenStore) writeFile(token string) error {
+	aead, err := s.cipher()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return i18n.Wrap(err, i18n.ErrRandom)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(token), nil)
+
+	path, err := s.filePath(tokenFileName)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(base64.StdEncoding.EncodeToString(sealed)), 0600); err != nil {
+		return i18n.Wrap(err, i18n.ErrSaveToken)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return i18n.Wrap(err, i18n.ErrSaveToken)
+	}
+	return nil
+}
+
+// removeFile 删除加密文件，文件不存在时不报错
+func (s *tokenStore) removeFile() error {
+	path, err := s.filePath(tokenFileName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return i18n.Wrap(err, i18n.ErrDeleteTokenFile)
+	}
+	return nil
+}
+
+// maskToken 隐藏 Token 的密钥部分，只保留 Bot ID 和最后 4 位
+func maskToken(token string) string {
+	id, secret, ok := strings.Cut(token, ":")
+	if !ok || len(secret) <= 4 {
+		return "****"
+	}
+	return id + ":****" + secret[len(secret)-4:]
+}
+
+// resolveBotToken 返回调用时传入的 Token，未传入时使用已保存的 Token
+func (m *MemeFile) resolveBotToken(botToken string) (string, error) {
+	if botToken = strings.TrimSpace(botToken); botToken != "" {
+		return botToken, nil
+	}
+
+	token, _, err := m.tokens.get()
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", i18n.Errorf(i18n.ErrBotTokenRequired)
+	}
+	return token, nil
+}
+
+// SetBotToken 保存 Telegram Bot Token，优先使用系统凭据存储
+func (m *MemeFile) SetBotToken(token string) (BotTokenStatus, error) {
+	token = strings.TrimSpace(token)
+	if !botTokenFormat.MatchString(token) {
+		return BotTokenStatus{}, i18n.Errorf(i18n.ErrBotTokenFormat)
+	}
+
+	storage, err := m.tokens.set(token)
+	if err != nil {
+		return BotTokenStatus{}, i18n.Errorf(i18n.ErrSaveBotToken, sticker.RedactToken(err.Error()))
+	}
+	return BotTokenStatus{Saved: true, Storage: storage, Masked: maskToken(token)}, nil
+}
+
+// GetBotTokenStatus 获取 Bot Token 的保存状态
+func (m *MemeFile) GetBotTokenStatus() BotTokenStatus {
+	token, storage, err := m.tokens.get()
+	if err != nil {
+		log.Printf("读取 Bot Token 失败: %v", err)
+	}
+	if token == "" {
+		return BotTokenStatus{Storage: TokenStorageNone}
+	}
+	return BotTokenStatus{Saved: true, Storage: storage, Masked: maskToken(token)}
+}
+
+// ClearBotToken 删除已保存的 Bot Token
+func (m *MemeFile) ClearBotToken() error {
+	return m.tokens.clear()
+}
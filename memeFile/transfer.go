@@ -0,0 +1,225 @@
+package memeFile
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 目标文件夹中已有同名文件时的处理方式
+const (
+	ConflictSkip      = "skip"      // 跳过该文件
+	ConflictRename    = "rename"    // 自动改名为 name (2).png
+	ConflictOverwrite = "overwrite" // 新文件写入成功后覆盖，已有文件移动到回收站
+)
+
+// 单个文件的处理结果
+const (
+	TransferDone        = "done"        // 已移动或复制，名称不变
+	TransferRenamed     = "renamed"     // 因重名自动改名
+	TransferOverwritten = "overwritten" // 覆盖了已有文件
+	TransferSkipped     = "skipped"     // 因重名跳过
+	TransferFailed      = "failed"      // 失败
+)
+
+// TransferItem 结构体 - 单个文件的移动/复制结果
+type TransferItem struct {
+	Name      string `json:"name"`      // 原文件名
+	FinalName string `json:"finalName"` // 目标文件夹中的最终文件名，跳过或失败时为空
+	Status    string `json:"status"`    // 处理结果
+	ErrorCode string `json:"errorCode"` // 失败的错误码
+	Error     string `json:"error"`     // 失败原因
+}
+
+// TransferResult 结构体 - 一次移动/复制的结果
+type TransferResult struct {
+	From      string         `json:"from"`      // 源分类文件夹
+	To        string         `json:"to"`        // 目标分类文件夹
+	Items     []TransferItem `json:"items"`     // 每个文件的结果，顺序与请求一致
+	Succeeded int            `json:"succeeded"` // 成功数量
+	Skipped   int            `json:"skipped"`   // 跳过数量
+	Failed    int            `json:"failed"`    // 失败数量
+}
+
+// MoveMemes 将表情从一个分类文件夹移动到另一个分类文件夹，跨磁盘时自动复制后删除
+func (m *MemeFile) MoveMemes(rootPath string, fromCode string, toCode string, fileNames []string, conflict string) (*TransferResult, error) {
+	return m.transferMemes(rootPath, fromCode, toCode, fileNames, conflict, true)
+}
+
+// CopyMemes 将表情复制到另一个分类文件夹
+func (m *MemeFile) CopyMemes(rootPath string, fromCode string, toCode string, fileNames []string, conflict string) (*TransferResult, error) {
+	return m.transferMemes(rootPath, fromCode, toCode, fileNames, conflict, false)
+}
+
+// transferMemes 移动或复制表情，单个文件失败不影响其他文件
+func (m *MemeFile) transferMemes(rootPath string, fromCode string, toCode string, fileNames []string, conflict string, move bool) (*TransferResult, error) {
+	if len(fileNames) == 0 {
+		return nil, i18n.Errorf(i18n.ErrFileListRequired)
+	}
+	if conflict == "" {
+		conflict = ConflictSkip
+	}
+	if conflict != ConflictSkip && conflict != ConflictRename && conflict != ConflictOverwrite {
+		return nil, i18n.Errorf(i18n.ErrUnsupportedConflict, conflict)
+	}
+
+	fromDir, err := resolvePath(rootPath, fromCode)
+	if err != nil {
+		return nil, err
+	}
+	toDir, err := resolvePath(rootPath, toCode)
+	if err != nil {
+		return nil, err
+	}
+	if fromDir == toDir {
+		return nil, i18n.Errorf(i18n.ErrSameFolder, fromCode)
+	}
+	if !m.fileUtils.IsDir(fromDir) {
+		return nil, i18n.Errorf(i18n.ErrFolderNotFound, fromCode)
+	}
+	if !m.fileUtils.IsDir(toDir) {
+		return nil, i18n.Errorf(i18n.ErrFolderNotFound, toCode)
+	}
+
+	result := &TransferResult{From: fromCode, To: toCode, Items: []TransferItem{}}
+	for _, name := range fileNames {
+		item := m.transferMeme(fromDir, toDir, name, conflict, move)
+		switch item.Status {
+		case TransferSkipped:
+			result.Skipped++
+		case TransferFailed:
+			result.Failed++
+		default:
+			result.Succeeded++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	// 全部失败时返回第一个错误，部分失败由调用方根据结果提示
+	if result.Succeeded == 0 && result.Failed > 0 {
+		for _, item := range result.Items {
+			if item.Status == TransferFailed {
+				return result, i18n.Errorf(i18n.ErrTransferFailed, result.Failed, item.Error)
+			}
+		}
+	}
+	return result, nil
+}
+
+// transferMeme 移动或复制单个文件
+func (m *MemeFile) transferMeme(fromDir string, toDir string, name string, conflict string, move bool) TransferItem {
+	item := TransferItem{Name: name}
+	fail := func(err error) TransferItem {
+		item.FinalName = ""
+		item.Status = TransferFailed
+		item.ErrorCode = string(i18n.CodeOf(err))
+		item.Error = err.Error()
+		return item
+	}
+
+	src, err := resolvePath(fromDir, name)
+	if err != nil {
+		return fail(err)
+	}
+	if !m.fileUtils.IsFile(src) {
+		return fail(i18n.Errorf(i18n.ErrFileNotFound, name))
+	}
+
+	item.FinalName = name
+	item.Status = TransferDone
+	dst := filepath.Join(toDir, name)
+	overwrite := false
+
+	if m.fileUtils.PathExists(dst) {
+		switch conflict {
+		case ConflictSkip:
+			item.FinalName = ""
+			item.Status = TransferSkipped
+			return item
+		case ConflictRename:
+			item.FinalName = m.uniqueName(toDir, name)
+			item.Status = TransferRenamed
+			dst = filepath.Join(toDir, item.FinalName)
+		case ConflictOverwrite:
+			overwrite = true
+			item.Status = TransferOverwritten
+		}
+	}
+
+	// 覆盖时先写入目标文件夹中的临时文件，成功后再替换已有文件，失败时已有文件保持不变
+	target := dst
+	if overwrite {
+		target = filepath.Join(toDir, m.uniqueName(toDir, "."+name+".transfer"))
+	}
+
+	if move {
+		if err := m.fileUtils.Move(src, target); err != nil {
+			return fail(i18n.Wrap(err, i18n.ErrMoveFile, name))
+		}
+	} else {
+		if err := m.fileUtils.Copy(src, target); err != nil {
+			// 目标已存在时不是本次创建的文件，不能删除
+			if !errors.Is(err, fs.ErrExist) {
+				os.Remove(target)
+			}
+			return fail(i18n.Wrap(err, i18n.ErrCopyFile, name))
+		}
+	}
+
+	if overwrite {
+		if err := m.replaceWithTransferred(src, target, dst, move); err != nil {
+			return fail(err)
+		}
+	}
+	return item
+}
+
+// replaceWithTransferred 将已有文件移动到回收站（误操作时可以找回），再将临时文件重命名为目标文件
+// 失败时恢复已有文件，并将临时文件移回源文件夹（移动）或删除（复制）
+func (m *MemeFile) replaceWithTransferred(src string, temp string, dst string, move bool) error {
+	name := filepath.Base(dst)
+	discard := func() {
+		if move {
+			if err := m.fileUtils.Move(temp, src); err != nil {
+				log.Printf("移回源文件失败 %s: %v", src, err)
+			}
+		} else {
+			os.Remove(temp)
+		}
+	}
+
+	trashed, err := m.moveToTrash(dst, "")
+	if err != nil {
+		discard()
+		return i18n.Wrap(err, i18n.ErrDeleteFile)
+	}
+	if err := os.Rename(temp, dst); err != nil {
+		if _, restoreErr := m.restoreTrashItem(trashed.ID); restoreErr != nil {
+			log.Printf("恢复被覆盖的文件失败 %s: %v", dst, restoreErr)
+		}
+		discard()
+		if move {
+			return i18n.Wrap(err, i18n.ErrMoveFile, name)
+		}
+		return i18n.Wrap(err, i18n.ErrCopyFile, name)
+	}
+	return nil
+}
+
+// uniqueName 生成目标文件夹中不存在的文件名：name (2).png、name (3).png ...
+func (m *MemeFile) uniqueName(dir string, name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if !m.fileUtils.PathExists(filepath.Join(dir, candidate)) {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package memeFile
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"mymeme/memeFile/i18n"
+)
+
+// 回收站目录和索引文件名，都在应用配置目录下
+const (
+	trashDirName       = "trash"
+	trashIndexFileName = "trash.json"
+)
+
+// defaultTrashRetentionDays 回收站中的条目默认保留天数
+const defaultTrashRetentionDays = 30
+
+// TrashItem 结构体 - 回收站中的一个文件或文件夹
+type TrashItem struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`         // 文件或文件夹名
+	OriginalPath string `json:"originalPath"` // 删除前的完整路径
+	DeletedAt    int64  `json:"deletedAt"`    // 删除时间（Unix 毫秒）
+	IsDir        bool   `json:"isDir"`        // 是否为文件夹（贴纸集合）
+	Size         int64  `json:"size"`         // 占用空间（字节）
+}
+
+// trashPath 获取回收站中条目的保存路径：trash/<ID>/<名称>
+func (m *MemeFile) trashPath(item TrashItem) (string, error) {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, trashDirName, item.ID, item.Name), nil
+}
+
+// loadTrash 读取回收站索引，调用方需持有 trashMu
+func (m *MemeFile) loadTrash() []TrashItem {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return nil
+	}
+
+	var items []TrashItem
+	if err := m.fileUtils.ReadJSON(filepath.Join(dir, trashIndexFileName), &items); err != nil && !os.IsNotExist(err) {
+		log.Printf("读取回收站索引失败: %v", err)
+	}
+	return items
+}
+
+// saveTrash 保存回收站索引，调用方需持有 trashMu
+func (m *MemeFile) saveTrash(items []TrashItem) error {
+	dir, err := m.fileUtils.AppDataDir()
+	if err != nil {
+		return err
+	}
+	if err := m.fileUtils.WriteJSON(filepath.Join(dir, trashIndexFileName), items); err != nil {
+		return i18n.Wrap(err, i18n.ErrSaveTrash)
+	}
+	return nil
+}
+
+// removeTrashData 永久删除条目在回收站中的数据
+func (m *MemeFile) removeTrashData(item TrashItem) {
+	path, err := m.trashPath(item)
+	if err != nil {
+		return
+	}
+	if err := os.RemoveAll(filepath.Dir(path)); err != nil {
+		log.Printf("删除回收站数据失败 %s: %v", item.Name, err)
+	}
+}
+
+// moveToTrash 将文件或文件夹移动到回收站，id 为空时生成新ID
+func (m *MemeFile) moveToTrash(path string, id string) (TrashItem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return TrashItem{}, err
+	}
+	if id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	item := TrashItem{
+		ID:           id,
+		Name:         filepath.Base(path),
+		OriginalPath: path,
+		DeletedAt:    time.Now().UnixMilli(),
+		IsDir:        info.IsDir(),
+		Size:         pathSize(path),
+	}
+
+	stored, err := m.trashPath(item)
+	if err != nil {
+		return TrashItem{}, err
+	}
+
+	m.trashMu.Lock()
+	defer m.trashMu.Unlock()
+
+	if err := m.fileUtils.Move(path, stored); err != nil {
+		return TrashItem{}, err
+	}
+	if err := m.saveTrash(append(m.loadTrash(), item)); err != nil {
+		// 索引写入失败时移回原位置，避免文件无法找回
+		if moveErr := m.fileUtils.Move(stored, path); moveErr != nil {
+			log.Printf("移回回收站文件失败 %s: %v", path, moveErr)
+		}
+		return TrashItem{}, err
+	}
+	return item, nil
+}
+
+// restoreTrashItem 将回收站中的条目移回原位置
+func (m *MemeFile) restoreTrashItem(id string) (TrashItem, error) {
+	m.trashMu.Lock()
+	defer m.trashMu.Unlock()
+
+	items := m.loadTrash()
+	for i, item := range items {
+		if item.ID != id {
+			continue
+		}
+
+		if m.fileUtils.PathExists(item.OriginalPath) {
+			return item, i18n.Errorf(i18n.ErrFileExists, item.Name)
+		}
+		stored, err := m.trashPath(item)
+		if err != nil {
+			return item, err
+		}
+		if err := m.fileUtils.Move(stored, item.OriginalPath); err != nil {
+			return item, i18n.Wrap(err, i18n.ErrRestoreFile, item.Name)
+		}
+
+		m.removeTrashData(item)
+		if err := m.saveTrash(append(items[:i:i], items[i+1:]...)); err != nil {
+			log.Printf("更新回收站索引失败: %v", err)
+		}
+		return item, nil
+	}
+
+	return TrashItem{}, i18n.Errorf(i18n.ErrTrashItemNotFound, id)
+}
+
+// trashRetentionDays 获取回收站保留天数，0 表示不自动清理
+func (m *MemeFile) trashRetentionDays() int {
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	return m.settings.TrashRetentionDays
+}
+
+// purgeTrash 永久删除超过保留天数的条目
+func (m *MemeFile) purgeTrash() {
+	days := m.trashRetentionDays()
+	if days <= 0 {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, -days).UnixMilli()
+
+	m.trashMu.Lock()
+	defer m.trashMu.Unlock()
+
+	items := m.loadTrash()
+	kept := items[:0]
+	for _, item := range items {
+		if item.DeletedAt < cutoff {
+			m.removeTrashData(item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) == len(items) {
+		return
+	}
+	if err := m.saveTrash(kept); err != nil {
+		log.Printf("更新回收站索引失败: %v", err)
+	}
+}
+
+// pathSize 计算文件或文件夹的大小
+func pathSize(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// ListTrash 获取回收站中的条目，最近删除的在前，同时清理过期条目
+func (m *MemeFile) ListTrash() []TrashItem {
+	m.purgeTrash()
+
+	m.trashMu.Lock()
+	defer m.trashMu.Unlock()
+
+	items := m.loadTrash()
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt > items[j].DeletedAt
+	})
+	return items
+}
+
+// RestoreFromTrash 将回收站中的条目恢复到原位置，原位置已有同名文件时返回错误
+func (m *MemeFile) RestoreFromTrash(id string) (TrashItem, error) {
+	return m.restoreTrashItem(id)
+}
+
+// EmptyTrash 清空回收站，永久删除所有条目
+func (m *MemeFile) EmptyTrash() error {
+	m.trashMu.Lock()
+	defer m.trashMu.Unlock()
+
+	for _, item := range m.loadTrash() {
+		m.removeTrashData(item)
+	}
+	return m.saveTrash([]TrashItem{})
+}
+
+// SetTrashRetentionDays 设置回收站保留天数，0 表示不自动清理
+func (m *MemeFile) SetTrashRetentionDays(days int) error {
+	if days < 0 || days > 365 {
+		return i18n.Errorf(i18n.ErrTrashRetentionRange, days)
+	}
+
+	m.settingsMu.Lock()
+	m.settings.TrashRetentionDays = days
+	err := m.saveSettings()
+	m.settingsMu.Unlock()
+
+	m.purgeTrash()
+	return err
+}
+
+// GetTrashRetentionDays 获取回收站保留天数
+func (m *MemeFile) GetTrashRetentionDays() int {
+	return m.trashRetentionDays()
+}
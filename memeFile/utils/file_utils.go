@@ -1,10 +1,17 @@
 package utils
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+
+	"mymeme/memeFile/i18n"
 )
 
+// appDirName 应用配置目录名
+const appDirName = "QQmeme"
+
 type FileUtils struct{}
 
 func NewFileUtils() *FileUtils {
@@ -83,3 +90,109 @@ func (f *FileUtils) JoinPath(elem ...string) string {
 func (f *FileUtils) GetFileExt(fileName string) string {
 	return filepath.Ext(fileName)
 }
+
+// AppDataDir 获取应用配置目录（如 Windows 下的 %AppData%\QQmeme），不存在时自动创建
+func (f *FileUtils) AppDataDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", i18n.Wrap(err, i18n.ErrConfigDir)
+	}
+
+	dir := filepath.Join(configDir, appDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", i18n.Wrap(err, i18n.ErrAppDataDir)
+	}
+	return dir, nil
+}
+
+// ReadJSON 读取 JSON 文件并解析到 v
+func (f *FileUtils) ReadJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON 将 v 写入 JSON 文件
+// 先写入临时文件再重命名，避免写入中断导致原文件损坏
+func (f *FileUtils) WriteJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// Move 移动文件或文件夹，目标所在目录不存在时自动创建
+// 跨磁盘无法直接重命名时先复制再删除原文件
+func (f *FileUtils) Move(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	} else if f.PathExists(dst) || !f.PathExists(src) {
+		return err
+	}
+
+	if err := f.Copy(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// Copy 复制文件或文件夹（递归），保留文件权限
+func (f *FileUtils) Copy(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return copyFile(src, dst, info.Mode())
+	}
+
+	if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := f.Copy(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile 复制单个文件
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 文件名问题类型
+const (
+	NameProblemURLReserved = "url_reserved"     // 包含在图片地址中有特殊含义的字符，如 % # ? & { }
+	NameProblemInvalidChar = "invalid_char"     // 包含 Windows 不允许的字符或控制字符
+	NameProblemNotNFC      = "not_nfc"          // Unicode 不是 NFC 规范形式（常见于 MacOS 生成的文件名）
+	NameProblemEdge        = "edge_dot_space"   // 以空格开头，或以点、空格结尾
+	NameProblemReserved    = "windows_reserved" // Windows 保留名称，如 CON、NUL、COM1
+)
+
+// urlReservedChars 在图片地址中会被解析为转义、片段或查询参数的字符
+const urlReservedChars = "%#?&{}[]^`"
+
+// invalidNameChars Windows 文件名不允许的字符
+const invalidNameChars = `<>:"/\|*`
+
+// windowsReservedNames Windows 保留的设备名，不区分大小写，带扩展名也不允许
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// NameIssue 结构体 - 一个需要修正的文件名
+type NameIssue struct {
+	Dir      string   `json:"dir"`      // 所在目录
+	Name     string   `json:"name"`     // 当前名称
+	NewName  string   `json:"newName"`  // 修正后的名称
+	IsDir    bool     `json:"isDir"`    // 是否为文件夹
+	Problems []string `json:"problems"` // 问题类型
+	Error    string   `json:"error"`    // 重命名失败原因
+}
+
+// SanitizeName 检查并修正文件名，返回修正后的名称和发现的问题，没有问题时返回原名称
+// 问题字符替换为下划线，Unicode 转为 NFC，去掉首部空格和尾部的点、空格，保留名称后加下划线
+func (f *FileUtils) SanitizeName(name string) (string, []string) {
+	var problems []string
+	addProblem := func(p string) {
+		for _, existing := range problems {
+			if existing == p {
+				return
+			}
+		}
+		problems = append(problems, p)
+	}
+
+	if !norm.NFC.IsNormalString(name) {
+		addProblem(NameProblemNotNFC)
+		name = norm.NFC.String(name)
+	}
+
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(urlReservedChars, r):
+			addProblem(NameProblemURLReserved)
+			return '_'
+		case r < 0x20 || r == 0x7f || strings.ContainsRune(invalidNameChars, r):
+			addProblem(NameProblemInvalidChar)
+			return '_'
+		}
+		return r
+	}, name)
+
+	if trimmed := strings.TrimRight(strings.TrimLeft(name, " "), ". "); trimmed != name {
+		addProblem(NameProblemEdge)
+		name = trimmed
+	}
+	if name == "" {
+		name = "_"
+	}
+
+	base := name
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		addProblem(NameProblemReserved)
+		name = base + "_" + name[len(base):]
+	}
+
+	return name, problems
+}
+
+// ScanNames 检查根目录下的分类文件夹及其中的文件，返回需要修正的名称（只检查，不重命名）
+// 跳过以点开头的隐藏文件和文件夹；修正后的名称与已有名称冲突时自动加序号
+// 返回顺序为先文件后文件夹，按顺序重命名即可保证路径有效
+func (f *FileUtils) ScanNames(root string) []NameIssue {
+	var fileIssues, dirIssues []NameIssue
+
+	for _, dir := range f.GetDirs(root) {
+		if strings.HasPrefix(dir, ".") {
+			continue
+		}
+		dirPath := filepath.Join(root, dir)
+		fileIssues = append(fileIssues, f.scanDir(dirPath, f.GetFiles(dirPath), false)...)
+	}
+	dirIssues = f.scanDir(root, f.GetDirs(root), true)
+
+	return append(fileIssues, dirIssues...)
+}
+
+// scanDir 检查同一目录下的名称，保证修正后的名称互不冲突
+func (f *FileUtils) scanDir(dir string, names []string, isDir bool) []NameIssue {
+	entries, _ := os.ReadDir(dir)
+	taken := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		taken[strings.ToLower(entry.Name())] = true
+	}
+
+	var issues []NameIssue
+	for _, name := range names {
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		newName, problems := f.SanitizeName(name)
+		if len(problems) == 0 {
+			continue
+		}
+
+		newName = uniqueSanitizedName(newName, taken, isDir)
+		taken[strings.ToLower(newName)] = true
+		issues = append(issues, NameIssue{Dir: dir, Name: name, NewName: newName, IsDir: isDir, Problems: problems})
+	}
+	return issues
+}
+
+// uniqueSanitizedName 名称已被占用时加序号：name_2.png、name_3.png ...
+// 按不区分大小写比较，避免在 Windows 和 MacOS 上冲突
+func uniqueSanitizedName(name string, taken map[string]bool, isDir bool) string {
+	if !taken[strings.ToLower(name)] {
+		return name
+	}
+
+	ext := ""
+	if !isDir {
+		ext = filepath.Ext(name)
+	}
+	base := strings.TrimSuffix(name, ext)
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d%s", base, i, ext)
+		if !taken[strings.ToLower(candidate)] {
+			return candidate
+		}
+	}
+}